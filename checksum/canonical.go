@@ -0,0 +1,328 @@
+package checksum
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+	"unsafe"
+)
+
+// Tags of the canonical encoding, see Canonicalize.
+const (
+	CanonicalNil     byte = 0x00
+	CanonicalEmpty   byte = 0x01
+	CanonicalBool    byte = 0x02
+	CanonicalInt     byte = 0x03
+	CanonicalUint    byte = 0x04
+	CanonicalFloat   byte = 0x05
+	CanonicalComplex byte = 0x06
+	CanonicalString  byte = 0x07
+	CanonicalMap     byte = 0x10
+	CanonicalStruct  byte = 0x11
+	CanonicalList    byte = 0x12
+	CanonicalTyped   byte = 0x13
+	CanonicalBag     byte = 0x14
+	CanonicalSet     byte = 0x15
+)
+
+/*
+Canonicalize returns the canonical encoding of an input, which is the hash-independent form of the input that Checksum
+(with the zero Options) is calculated from. Implementations in other languages can decode it and re-calculate the checksum
+to verify checksums produced by this package.
+
+Each value is encoded as a 1-byte tag followed by its payload. Integers are big-endian; lengths and counts are uint64.
+A "str" below is a length-prefixed byte string: the uint64 length followed by the bytes.
+
+	tag   name     payload                                         digest D(v), given hash function H
+	0x00  nil      (none)                                          len(H("")) zero bytes
+	0x01  empty    (none)                                          the empty byte string (nil pointers nested in other values)
+	0x02  bool     1 byte: 0x00 or 0x01                            H(payload)
+	0x03  int      8 bytes, two's complement                       H(payload)
+	0x04  uint     8 bytes                                         H(payload)
+	0x05  float    8 bytes, IEEE 754 binary64                      H(payload)
+	0x06  complex  16 bytes: real then imaginary part, as floats   H(payload)
+	0x07  string   str                                             H(bytes of the string)
+	0x12  list     count, then count values                        F(D(v1), ..., D(vn))
+	0x10  map      count, then count entries: key, value           S(H("0x10"); E1, ..., En) where Ei = F(D(key), D(value))
+	0x11  struct   str type name, count, then count                S(H("0x11"), H(type name); E1, ..., En)
+	               entries: str field name, value                  where Ei = F(H(field name), D(value))
+	0x13  typed    str type name, count, then count values         F(H("0x11"), H(type name), D(v1), ..., D(vn))
+	0x14  bag      count, then count values                        S(H("0x12"); D(v1), ..., D(vn))
+	0x15  set      count, then count values                        S(H("0x12"); distinct digests of D(v1), ..., D(vn))
+
+Where:
+
+  - F(d1, ..., dn) folds digests in order: acc starts with the 4 ASCII bytes "0x12", then acc = H(acc || di) for each di.
+    Hence F() is "0x12" itself, e.g. the digest of an empty list.
+  - S(m1, ...; E1, ..., En) sorts the entry digests Ei by their lower-case hex-encoded forms,
+    then returns F(m1, ..., H(hex(E1)), ..., H(hex(En))).
+
+Entries of maps and structs, as well as values of bags and sets, are emitted sorted by their encoded bytes, so that the
+encoding is deterministic. Bags and sets are slices/arrays hashed order-independently, see SliceOrder; note that values of
+a set are not de-duplicated in the encoding, as different values may have the same digest (e.g. int(1) and uint(1)).
+The typed tag is used for values that define their own canonical forms:
+
+  - time.Time and named types of time.Time: type name "time.Time" and one int value, the UnixNano of the time.
+  - encoding.BinaryMarshaler/encoding.TextMarshaler: the type name and one string value, the marshaled data.
+  - structs with the legacy Checksum() method: the type name and the values returned by the method.
+
+Values implementing Checksummer have no canonical form, as their checksums depend on the hash function: Canonicalize returns
+an error for them. An UnsupportedKindError is returned for values of unsupported kinds (chan, func and unsafe.Pointer).
+Circular references are encoded as nil, the same way Checksum treats them.
+
+@Available since <<VERSION>>
+*/
+func Canonicalize(v interface{}) ([]byte, error) {
+	e := &canonicalEncoder{visited: make(map[uintptr]struct{})}
+	buf := new(bytes.Buffer)
+	if v == nil {
+		buf.WriteByte(CanonicalNil)
+		return buf.Bytes(), nil
+	}
+	_, rv := Unwrap(v)
+	if !rv.IsValid() {
+		buf.WriteByte(CanonicalNil)
+		return buf.Bytes(), nil
+	}
+	e.encodeValue(buf, reflect.ValueOf(rv.Interface()))
+	if e.err != nil {
+		return nil, e.err
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalEncoder holds the state of a Canonicalize call. It walks values the same way checksumValue does (with the
+// zero Options); changes to either walker must keep TestCanonicalize_AgreesWithChecksum passing.
+type canonicalEncoder struct {
+	visited map[uintptr]struct{}
+	path    []string
+	err     error
+
+	nextSliceOrder SliceOrder
+}
+
+func (e *canonicalEncoder) encodeChild(buf *bytes.Buffer, segment pathSegment, rv reflect.Value) {
+	e.path = append(e.path, segment.String())
+	e.encodeValue(buf, rv)
+	e.path = e.path[:len(e.path)-1]
+}
+
+func writeCanonicalCount(buf *bytes.Buffer, n int) {
+	buf.Write(uintToBytes(uint64(n)))
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) {
+	writeCanonicalCount(buf, len(s))
+	buf.WriteString(s)
+}
+
+// writeCanonicalEntries writes the entries sorted by their encoded bytes.
+func writeCanonicalEntries(buf *bytes.Buffer, entries [][]byte) {
+	sort.Sort(byteSlices(entries))
+	writeCanonicalCount(buf, len(entries))
+	for _, entry := range entries {
+		buf.Write(entry)
+	}
+}
+
+func (e *canonicalEncoder) encodeValue(buf *bytes.Buffer, rv reflect.Value) {
+	sliceOrder := e.nextSliceOrder
+	e.nextSliceOrder = SliceOrdered
+	if !rv.IsValid() || (rv.Kind() == reflect.Interface && rv.IsNil()) {
+		buf.WriteByte(CanonicalNil)
+		return
+	}
+	var prv reflect.Value
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.Kind() == reflect.Ptr {
+			prv = rv
+		}
+		rv = rv.Elem()
+	}
+
+	var ptr uintptr
+	tracked := false
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice:
+		ptr, tracked = rv.Pointer(), true
+	default:
+		if prv.IsValid() && !prv.IsNil() {
+			ptr, tracked = prv.Pointer(), true
+		}
+	}
+	if tracked {
+		if _, ok := e.visited[ptr]; ok {
+			buf.WriteByte(CanonicalNil)
+			return
+		}
+		e.visited[ptr] = struct{}{}
+		defer delete(e.visited, ptr)
+	}
+
+	if !rv.IsValid() {
+		buf.WriteByte(CanonicalEmpty)
+		return
+	}
+	plan := typePlanOf(rv.Type())
+	if plan.hasCustom && e.encodeCustom(buf, plan, prv, rv) {
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		buf.WriteByte(CanonicalBool)
+		buf.Write(boolToBytes(rv.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteByte(CanonicalInt)
+		buf.Write(intToBytes(rv.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteByte(CanonicalUint)
+		buf.Write(uintToBytes(rv.Uint()))
+	case reflect.Float32, reflect.Float64:
+		buf.WriteByte(CanonicalFloat)
+		buf.Write(floatToBytes(rv.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := rv.Complex()
+		buf.WriteByte(CanonicalComplex)
+		buf.Write(floatToBytes(real(c)))
+		buf.Write(floatToBytes(imag(c)))
+	case reflect.String:
+		buf.WriteByte(CanonicalString)
+		writeCanonicalString(buf, rv.String())
+	case reflect.Array, reflect.Slice:
+		if sliceOrder != SliceOrdered {
+			values := make([][]byte, 0, rv.Len())
+			for i, n := 0, rv.Len(); i < n; i++ {
+				value := new(bytes.Buffer)
+				e.encodeChild(value, pathSegment{index: i}, rv.Index(i))
+				values = append(values, value.Bytes())
+			}
+			if sliceOrder == SliceSet {
+				buf.WriteByte(CanonicalSet)
+			} else {
+				buf.WriteByte(CanonicalBag)
+			}
+			writeCanonicalEntries(buf, values)
+			break
+		}
+		buf.WriteByte(CanonicalList)
+		writeCanonicalCount(buf, rv.Len())
+		for i, n := 0, rv.Len(); i < n; i++ {
+			e.encodeChild(buf, pathSegment{index: i}, rv.Index(i))
+		}
+	case reflect.Map:
+		entries := make([][]byte, 0, rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			key := iter.Key()
+			segment := pathSegment{key: key}
+			entry := new(bytes.Buffer)
+			e.encodeChild(entry, segment, key)
+			e.encodeChild(entry, segment, iter.Value())
+			entries = append(entries, entry.Bytes())
+		}
+		buf.WriteByte(CanonicalMap)
+		writeCanonicalEntries(buf, entries)
+	case reflect.Struct:
+		if plan.hasUnexported && !rv.CanAddr() {
+			temp := reflect.New(rv.Type()).Elem()
+			temp.Set(rv)
+			rv = temp
+		}
+		entries := make([][]byte, 0, len(plan.fields))
+		for _, field := range plan.fields {
+			fieldValue := rv.Field(field.index)
+			if !field.exported {
+				fieldValue = reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr())).Elem()
+			}
+			entry := new(bytes.Buffer)
+			writeCanonicalString(entry, field.name)
+			e.nextSliceOrder = field.sliceOrder
+			e.encodeChild(entry, pathSegment{name: field.name}, fieldValue)
+			entries = append(entries, entry.Bytes())
+		}
+		buf.WriteByte(CanonicalStruct)
+		writeCanonicalString(buf, plan.typeName)
+		writeCanonicalEntries(buf, entries)
+	default:
+		if e.err == nil {
+			e.err = &UnsupportedKindError{Path: joinPath(e.path), Kind: rv.Kind(), Type: rv.Type()}
+		}
+		writeCanonicalTyped(buf, plan.typeName)
+	}
+}
+
+// writeCanonicalTyped writes the header of a typed value: the tag, the type name and the number of values that follow.
+func writeCanonicalTyped(buf *bytes.Buffer, typeName string, values ...[]byte) {
+	buf.WriteByte(CanonicalTyped)
+	writeCanonicalString(buf, typeName)
+	writeCanonicalCount(buf, len(values))
+	for _, value := range values {
+		buf.Write(value)
+	}
+}
+
+// canonicalString returns the canonical encoding of a string value.
+func canonicalString(data []byte) []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteByte(CanonicalString)
+	writeCanonicalCount(buf, len(data))
+	buf.Write(data)
+	return buf.Bytes()
+}
+
+// encodeCustom encodes values that define their own canonical forms, following the same precedence as customChecksum.
+//
+// It returns false if v does not define its own canonical form.
+func (e *canonicalEncoder) encodeCustom(buf *bytes.Buffer, plan *typePlan, prv, rv reflect.Value) bool {
+	if plan.checksummer != implNone {
+		if e.err == nil {
+			e.err = fmt.Errorf("value of type [%s] at path [%s] implements Checksummer and has no canonical form", plan.typeName, joinPath(e.path))
+		}
+		writeCanonicalTyped(buf, plan.typeName)
+		return true
+	}
+
+	if plan.legacyMethod >= 0 {
+		var m reflect.Value
+		if !plan.legacyOnPointer {
+			m = rv.Method(plan.legacyMethod)
+		} else if prv.IsValid() && prv.Type().Elem() == rv.Type() {
+			m = prv.Method(plan.legacyMethod)
+		}
+		if m.IsValid() {
+			var values [][]byte
+			for _, vtemp := range m.Call(nil) {
+				value := new(bytes.Buffer)
+				e.encodeValue(value, reflect.ValueOf(vtemp.Interface()))
+				values = append(values, value.Bytes())
+			}
+			writeCanonicalTyped(buf, plan.typeName, values...)
+			return true
+		}
+	}
+
+	if plan.isTime {
+		nano := new(bytes.Buffer)
+		nano.WriteByte(CanonicalInt)
+		nano.Write(intToBytes(rv.Convert(typeTime).Interface().(time.Time).UnixNano()))
+		writeCanonicalTyped(buf, typeTime.String(), nano.Bytes())
+		return true
+	}
+
+	if plan.binaryMarshaler != implNone {
+		if data, err := implementer(plan.binaryMarshaler, prv, rv).Interface().(encoding.BinaryMarshaler).MarshalBinary(); err == nil {
+			writeCanonicalTyped(buf, plan.typeName, canonicalString(data))
+			return true
+		}
+	}
+	if plan.textMarshaler != implNone {
+		if data, err := implementer(plan.textMarshaler, prv, rv).Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			writeCanonicalTyped(buf, plan.typeName, canonicalString(data))
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,435 @@
+package checksum
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"math/rand"
+	"net"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+var updateCanonicalVectors = flag.Bool("update-canonical", false, "update testdata/canonical_vectors.json")
+
+const canonicalVectorsFile = "testdata/canonical_vectors.json"
+
+// canonicalVector is a golden test vector: the canonical encoding of a value and its checksums.
+type canonicalVector struct {
+	Name      string            `json:"name"`
+	Canonical string            `json:"canonical"`
+	Digests   map[string]string `json:"digests"`
+}
+
+type MyStructCanonical struct {
+	ID        int    `checksum:"id"`
+	Name      string `checksum:"name"`
+	Tags      []string
+	UpdatedAt time.Time `checksum:"-"`
+	Parent    *MyStructCanonical
+	secret    string
+}
+
+type MyStructCanonicalUnordered struct {
+	ID     int
+	Roles  []string      `checksum:"roles,set"`
+	Scores []interface{} `checksum:",unordered"`
+}
+
+// canonicalVectorValues are values of the golden test vectors, by name.
+func canonicalVectorValues() map[string]interface{} {
+	t := time.Date(2021, 2, 3, 4, 5, 6, 7, time.UTC)
+	return map[string]interface{}{
+		"nil":            nil,
+		"bool_true":      true,
+		"bool_false":     false,
+		"int_103":        103,
+		"int_negative":   int8(-2),
+		"uint_max":       uint64(1<<64 - 1),
+		"float_10.3":     10.3,
+		"float_negative": -0.5,
+		"complex":        complex(1.5, -2),
+		"string":         "any thing",
+		"string_empty":   "",
+		"string_utf8":    "xin chào",
+		"list_empty":     []int{},
+		"list_ints":      []int{1, 2, 3},
+		"list_nested":    []interface{}{1, "a", []interface{}{true, nil}},
+		"map_empty":      map[string]int{},
+		"map_str_int":    map[string]int{"one": 1, "two": 2, "three": 3},
+		"map_int_mixed":  map[int]interface{}{1: "a", 2: []int{2}, 3: map[string]bool{"x": true}},
+		"time":           t,
+		"big_int":        big.NewInt(1234567890),
+		"ip":             net.ParseIP("192.168.1.1"),
+		"struct": MyStructCanonical{ID: 1, Name: "child", Tags: []string{"x", "y"}, UpdatedAt: t, secret: "s",
+			Parent: &MyStructCanonical{ID: 0, Name: "root"}},
+		"struct_unordered":       MyStructCanonicalUnordered{ID: 2, Roles: []string{"user", "admin", "user"}, Scores: []interface{}{1, uint(1), 2.5, "1"}},
+		"legacy_checksum_method": MyStructCustom1{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4},
+	}
+}
+
+var canonicalVectorHashes = map[string]HashFunc{"crc32": Crc32HashFunc, "md5": Md5HashFunc, "sha256": Sha256HashFunc}
+
+/*----------------------------------------------------------------------*/
+
+// canonicalDecoder re-calculates checksums from canonical encodings, following the spec of Canonicalize only.
+type canonicalDecoder struct {
+	hf   HashFunc
+	data []byte
+}
+
+func (d *canonicalDecoder) next(n int) []byte {
+	if len(d.data) < n {
+		panic(fmt.Sprintf("unexpected end of data, need %d bytes but %d remaining", n, len(d.data)))
+	}
+	result := d.data[:n]
+	d.data = d.data[n:]
+	return result
+}
+
+func (d *canonicalDecoder) count() int {
+	return int(binary.BigEndian.Uint64(d.next(8)))
+}
+
+func (d *canonicalDecoder) str() []byte {
+	return d.next(d.count())
+}
+
+func (d *canonicalDecoder) fold(digests ...[]byte) []byte {
+	acc := []byte("0x12")
+	for _, digest := range digests {
+		acc = d.hf(append(append([]byte{}, acc...), digest...))
+	}
+	return acc
+}
+
+func (d *canonicalDecoder) sorted(markers [][]byte, entries [][]byte) []byte {
+	hexList := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		hexList = append(hexList, hex.EncodeToString(entry))
+	}
+	sort.Strings(hexList)
+	for _, h := range hexList {
+		markers = append(markers, d.hf([]byte(h)))
+	}
+	return d.fold(markers...)
+}
+
+func (d *canonicalDecoder) digest() []byte {
+	switch tag := d.next(1)[0]; tag {
+	case CanonicalNil:
+		return make([]byte, len(d.hf(nil)))
+	case CanonicalEmpty:
+		return []byte{}
+	case CanonicalBool:
+		return d.hf(d.next(1))
+	case CanonicalInt, CanonicalUint, CanonicalFloat:
+		return d.hf(d.next(8))
+	case CanonicalComplex:
+		return d.hf(d.next(16))
+	case CanonicalString:
+		return d.hf(d.str())
+	case CanonicalList:
+		n := d.count()
+		digests := make([][]byte, 0, n)
+		for i := 0; i < n; i++ {
+			digests = append(digests, d.digest())
+		}
+		return d.fold(digests...)
+	case CanonicalMap:
+		n := d.count()
+		entries := make([][]byte, 0, n)
+		for i := 0; i < n; i++ {
+			entries = append(entries, d.fold(d.digest(), d.digest()))
+		}
+		return d.sorted([][]byte{d.hf([]byte("0x10"))}, entries)
+	case CanonicalStruct:
+		typeName := d.str()
+		n := d.count()
+		entries := make([][]byte, 0, n)
+		for i := 0; i < n; i++ {
+			entries = append(entries, d.fold(d.hf(d.str()), d.digest()))
+		}
+		return d.sorted([][]byte{d.hf([]byte("0x11")), d.hf(typeName)}, entries)
+	case CanonicalBag, CanonicalSet:
+		n := d.count()
+		digests := make([][]byte, 0, n)
+		for i := 0; i < n; i++ {
+			digest := d.digest()
+			if tag == CanonicalSet {
+				for _, existing := range digests {
+					if reflect.DeepEqual(existing, digest) {
+						digest = nil
+						break
+					}
+				}
+			}
+			if digest != nil {
+				digests = append(digests, digest)
+			}
+		}
+		return d.sorted([][]byte{d.hf([]byte("0x12"))}, digests)
+	case CanonicalTyped:
+		digests := [][]byte{d.hf([]byte("0x11")), d.hf(d.str())}
+		n := d.count()
+		for i := 0; i < n; i++ {
+			digests = append(digests, d.digest())
+		}
+		return d.fold(digests...)
+	default:
+		panic(fmt.Sprintf("unknown tag 0x%02x", tag))
+	}
+}
+
+func canonicalDigest(hf HashFunc, data []byte) []byte {
+	d := &canonicalDecoder{hf: hf, data: data}
+	digest := d.digest()
+	if len(d.data) != 0 {
+		panic(fmt.Sprintf("%d unexpected trailing bytes", len(d.data)))
+	}
+	return digest
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestCanonicalize(t *testing.T) {
+	testName := "TestCanonicalize"
+	now := time.Now()
+	node := &Node{Value: 1}
+	node.Next = node
+	vList := []interface{}{
+		nil, (*int)(nil), true, 1, -1, uint(1), 1.5, complex64(1 + 2i), "a string", now, &now,
+		[]int{1, 2, 3}, [3]int{1, 2, 3}, []interface{}{nil, (*int)(nil), []int(nil), map[string]int(nil)},
+		map[string]interface{}{"a": 1, "b": []string{"x", "y"}, "c": map[int]*time.Time{1: &now}},
+		MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4},
+		&MyStructAllPublic{S: "string", A: []interface{}{1, now}, M: map[string]interface{}{"t": &now}},
+		MyStructCustom2{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4},
+		MyMarshaler{Value: "v"}, big.NewInt(-1), node,
+		MyStructCanonicalUnordered{Roles: []string{"b", "a", "b"}, Scores: []interface{}{int(1), uint(1), []int{2, 1}, nil}},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, v := range vList {
+				data, err := Canonicalize(v)
+				if err != nil {
+					t.Fatalf("%s failed: %s", testName+"/"+name, err)
+				}
+				if expected, received := Checksum(hf, v), canonicalDigest(hf, data); !reflect.DeepEqual(expected, received) {
+					t.Fatalf("%s failed: <%#v> expected %x but received %x", testName+"/"+name, v, expected, received)
+				}
+			}
+		})
+	}
+}
+
+func TestCanonicalize_Deterministic(t *testing.T) {
+	testName := "TestCanonicalize_Deterministic"
+	v := map[string]interface{}{"a": 1, "b": map[int]string{1: "x", 2: "y", 3: "z"}, "c": MyStructAllPublic{S: "s", M: map[string]interface{}{"k": 1, "l": 2}}}
+	expected, _ := Canonicalize(v)
+	for i := 0; i < 10; i++ {
+		if received, _ := Canonicalize(v); !reflect.DeepEqual(expected, received) {
+			t.Fatalf("%s failed: expected %x but received %x", testName, expected, received)
+		}
+	}
+}
+
+func TestCanonicalize_Error(t *testing.T) {
+	testName := "TestCanonicalize_Error"
+	testCases := []struct {
+		name string
+		v    interface{}
+		path string
+	}{
+		{name: "checksummer", v: []interface{}{1, MyChecksummer{ID: "1"}}, path: "[1]"},
+		{name: "checksummer_ptr", v: map[string]interface{}{"a": &MyChecksummerPtr{ID: "1"}}, path: "a"},
+		{name: "chan", v: map[string]interface{}{"c": make(chan int)}, path: "c"},
+		{name: "func", v: []interface{}{func() {}}, path: "[0]"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			data, err := Canonicalize(testCase.v)
+			if err == nil || data != nil {
+				t.Fatalf("%s failed: expected error but received %x", testName+"/"+testCase.name, data)
+			}
+			var ukErr *UnsupportedKindError
+			if errors.As(err, &ukErr) && ukErr.Path != testCase.path {
+				t.Fatalf("%s failed: expected path %#v but received %#v", testName+"/"+testCase.name, testCase.path, ukErr.Path)
+			}
+		})
+	}
+}
+
+func TestCanonicalize_GoldenVectors(t *testing.T) {
+	testName := "TestCanonicalize_GoldenVectors"
+	values := canonicalVectorValues()
+	if *updateCanonicalVectors {
+		names := make([]string, 0, len(values))
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		vectors := make([]canonicalVector, 0, len(names))
+		for _, name := range names {
+			data, err := Canonicalize(values[name])
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+name, err)
+			}
+			vector := canonicalVector{Name: name, Canonical: hex.EncodeToString(data), Digests: make(map[string]string)}
+			for alg, hf := range canonicalVectorHashes {
+				vector.Digests[alg] = hex.EncodeToString(Checksum(hf, values[name]))
+			}
+			vectors = append(vectors, vector)
+		}
+		js, _ := json.MarshalIndent(vectors, "", "  ")
+		if err := ioutil.WriteFile(canonicalVectorsFile, append(js, '\n'), 0644); err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+	}
+
+	js, err := ioutil.ReadFile(canonicalVectorsFile)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	var vectors []canonicalVector
+	if err := json.Unmarshal(js, &vectors); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if len(vectors) != len(values) {
+		t.Fatalf("%s failed: expected %d vectors but received %d", testName, len(values), len(vectors))
+	}
+	for _, vector := range vectors {
+		t.Run(vector.Name, func(t *testing.T) {
+			v, ok := values[vector.Name]
+			if !ok {
+				t.Fatalf("%s failed: no value for vector %#v", testName, vector.Name)
+			}
+			data, err := Canonicalize(v)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+vector.Name, err)
+			}
+			if received := hex.EncodeToString(data); received != vector.Canonical {
+				t.Fatalf("%s failed: expected canonical form %s but received %s", testName+"/"+vector.Name, vector.Canonical, received)
+			}
+			for alg, expected := range vector.Digests {
+				hf := canonicalVectorHashes[alg]
+				if received := hex.EncodeToString(Checksum(hf, v)); received != expected {
+					t.Fatalf("%s failed: expected %s checksum %s but received %s", testName+"/"+vector.Name, alg, expected, received)
+				}
+				if received := hex.EncodeToString(canonicalDigest(hf, data)); received != expected {
+					t.Fatalf("%s failed: expected %s digest of canonical form %s but received %s", testName+"/"+vector.Name, alg, expected, received)
+				}
+			}
+		})
+	}
+}
+
+// randomCanonicalValue generates a random value covering the kinds, struct tags and custom canonical forms that
+// Checksum and Canonicalize handle, so that the two walkers can be checked against each other.
+func randomCanonicalValue(r *rand.Rand, depth int) interface{} {
+	t := time.Unix(r.Int63n(1<<40), r.Int63n(1e9)).UTC()
+	scalars := []func() interface{}{
+		func() interface{} { return nil },
+		func() interface{} { return r.Intn(2) == 0 },
+		func() interface{} { return r.Intn(100) - 50 },
+		func() interface{} { return int8(r.Intn(256) - 128) },
+		func() interface{} { return int16(r.Intn(1000)) },
+		func() interface{} { return int32(r.Int31()) },
+		func() interface{} { return r.Int63() },
+		func() interface{} { return uint(r.Intn(100)) },
+		func() interface{} { return uint8(r.Intn(256)) },
+		func() interface{} { return uint16(r.Intn(1000)) },
+		func() interface{} { return r.Uint32() },
+		func() interface{} { return r.Uint64() },
+		func() interface{} { return uintptr(r.Intn(1000)) },
+		func() interface{} { return float32(r.NormFloat64()) },
+		func() interface{} { return r.NormFloat64() },
+		func() interface{} { return math.Copysign(0, -1) },
+		func() interface{} { return float64(r.Intn(10)) },
+		func() interface{} { return complex(float32(r.NormFloat64()), float32(r.NormFloat64())) },
+		func() interface{} { return complex(r.NormFloat64(), r.NormFloat64()) },
+		func() interface{} { return fmt.Sprintf("s%d", r.Intn(5)) },
+		func() interface{} { return "" },
+		func() interface{} { return t },
+		func() interface{} { return &t },
+		func() interface{} { return MyTime(t) },
+		func() interface{} { return time.Duration(r.Int63()) },
+		func() interface{} { return json.Number(fmt.Sprintf("%d", r.Intn(100))) },
+		func() interface{} { return big.NewInt(r.Int63()) },
+		func() interface{} { return net.IPv4(10, 0, byte(r.Intn(256)), byte(r.Intn(256))) },
+		func() interface{} { return MyMarshaler{Value: fmt.Sprintf("v%d", r.Intn(5)), binary: r.Intn(2) == 0} },
+		func() interface{} { return MyEmbeddedTime{t, fmt.Sprintf("n%d", r.Intn(5))} },
+		func() interface{} { return MyStructCustom1{S: "s", N: uint(r.Intn(5)), F: r.Float64()} },
+		func() interface{} { return []int(nil) },
+		func() interface{} { return map[string]int(nil) },
+		func() interface{} { return (*int)(nil) },
+	}
+	if depth <= 0 || r.Intn(3) == 0 {
+		return scalars[r.Intn(len(scalars))]()
+	}
+	n := r.Intn(4)
+	switch r.Intn(8) {
+	case 0:
+		v := make([]interface{}, n)
+		for i := range v {
+			v[i] = randomCanonicalValue(r, depth-1)
+		}
+		return v
+	case 1:
+		return [2]interface{}{randomCanonicalValue(r, depth-1), randomCanonicalValue(r, depth-1)}
+	case 2:
+		v := make(map[string]interface{}, n)
+		for i := 0; i < n; i++ {
+			v[fmt.Sprintf("k%d", r.Intn(10))] = randomCanonicalValue(r, depth-1)
+		}
+		return v
+	case 3:
+		v := make(map[int]interface{}, n)
+		for i := 0; i < n; i++ {
+			v[r.Intn(10)] = randomCanonicalValue(r, depth-1)
+		}
+		return v
+	case 4:
+		v := randomCanonicalValue(r, depth-1)
+		return &v
+	case 5:
+		scores := make([]interface{}, n)
+		for i := range scores {
+			scores[i] = randomCanonicalValue(r, depth-1)
+		}
+		return MyStructCanonicalUnordered{ID: r.Intn(5), Roles: []string{"a", "b", "a"}[:r.Intn(4)], Scores: scores}
+	case 6:
+		return &MyStructCanonical{ID: r.Intn(5), Name: "n", Tags: []string{"x"}, UpdatedAt: t, secret: "s",
+			Parent: &MyStructCanonical{ID: r.Intn(5)}}
+	default:
+		return MyStructAllPublic{S: "s", A: []interface{}{randomCanonicalValue(r, depth-1)},
+			M: map[string]interface{}{"v": randomCanonicalValue(r, depth-1)}}
+	}
+}
+
+// TestCanonicalize_AgreesWithChecksum checks that the canonical encoding walker and the checksum walker agree on random
+// values: the digest re-calculated from the canonical form must equal Checksum, for all hash functions.
+func TestCanonicalize_AgreesWithChecksum(t *testing.T) {
+	testName := "TestCanonicalize_AgreesWithChecksum"
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 500; round++ {
+		v := randomCanonicalValue(r, 4)
+		data, err := Canonicalize(v)
+		if err != nil {
+			t.Fatalf("%s failed: <%#v> %s", testName, v, err)
+		}
+		for i, name := range nameList {
+			if expected, received := Checksum(hfList[i], v), canonicalDigest(hfList[i], data); !reflect.DeepEqual(expected, received) {
+				t.Fatalf("%s failed: <%#v> expected %x but received %x", testName+"/"+name, v, expected, received)
+			}
+		}
+	}
+}
@@ -2,16 +2,22 @@ package checksum
 
 import (
 	"bytes"
+	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
 	"crypto/sha512"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"hash"
 	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"math"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 	"unsafe"
@@ -50,6 +56,42 @@ var Sha512HashFunc HashFunc = func(input []byte) []byte {
 	return hashFunc(sha512.New(), input)
 }
 
+// Sha512_256HashFunc is a HashFunc that calculates hash value using SHA-512/256.
+var Sha512_256HashFunc HashFunc = func(input []byte) []byte {
+	return hashFunc(sha512.New512_256(), input)
+}
+
+var crc64EcmaTable = crc64.MakeTable(crc64.ECMA)
+
+// Crc64HashFunc is a HashFunc that calculates hash value using CRC64 (ECMA polynomial).
+var Crc64HashFunc HashFunc = func(input []byte) []byte {
+	return hashFunc(crc64.New(crc64EcmaTable), input)
+}
+
+// Fnv1a64HashFunc is a HashFunc that calculates hash value using 64-bit FNV-1a.
+var Fnv1a64HashFunc HashFunc = func(input []byte) []byte {
+	return hashFunc(fnv.New64a(), input)
+}
+
+// Fnv1a128HashFunc is a HashFunc that calculates hash value using 128-bit FNV-1a.
+var Fnv1a128HashFunc HashFunc = func(input []byte) []byte {
+	return hashFunc(fnv.New128a(), input)
+}
+
+// XxHash64HashFunc is a HashFunc that calculates hash value using xxHash64 (seed 0).
+var XxHash64HashFunc HashFunc = func(input []byte) []byte {
+	return hashFunc(NewXxHash64(), input)
+}
+
+// HmacHashFunc returns a HashFunc that calculates keyed hash value using HMAC with the provided hash constructor and key,
+// e.g. HmacHashFunc(sha256.New, key). Checksums calculated with a HMAC HashFunc can act as tamper-evident signatures.
+func HmacHashFunc(newHash func() hash.Hash, key []byte) HashFunc {
+	key = append([]byte{}, key...)
+	return func(input []byte) []byte {
+		return hashFunc(hmac.New(newHash, key), input)
+	}
+}
+
 func boolToBytes(v bool) []byte {
 	if v {
 		return []byte{1}
@@ -58,25 +100,17 @@ func boolToBytes(v bool) []byte {
 }
 
 func intToBytes(v int64) []byte {
-	buf := new(bytes.Buffer)
-	_ = binary.Write(buf, binary.BigEndian, v)
-	return buf.Bytes()
+	return uintToBytes(uint64(v))
 }
 
 func uintToBytes(v uint64) []byte {
-	buf := new(bytes.Buffer)
-	_ = binary.Write(buf, binary.BigEndian, v)
-	return buf.Bytes()
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, v)
+	return buf
 }
 
 func floatToBytes(v float64) []byte {
-	buf := new(bytes.Buffer)
-	_ = binary.Write(buf, binary.BigEndian, v)
-	return buf.Bytes()
-}
-
-func isExportedField(fieldName string) bool {
-	return len(fieldName) > 0 && string(fieldName[0]) == strings.ToUpper(string(fieldName[0]))
+	return uintToBytes(math.Float64bits(v))
 }
 
 func Unwrap(v interface{}) (prv reflect.Value, rv reflect.Value) {
@@ -97,20 +131,49 @@ Checksum calculates checksum of an input using the provided hash function.
   - If v is a scalar type (bool, int*, uint*, float* or string) or pointer to scala type: checksum value is straightforward calculation.
   - If v is a slice or array: checksum value is combination of all elements' checksums, in order. If v is empty (has 0 elements), empty []byte is returned.
   - If v is a map: checksum value is combination of all entries' checksums, order-independent.
-  - If v is a struct: if the struct has function `Checksum()` then use it to calculate checksum value; if v is time.Time then use its nanosecond to calculate checksum value; otherwise checksum value is combination of all fields' checksums, order-independent.
+  - If v implements Checksummer: its Checksum(hf) method is used to calculate checksum value.
+  - If v is a struct: if the struct has function `Checksum()` then use it to calculate checksum value; if v is time.Time (or a named type of time.Time) then use its nanosecond to calculate checksum value; otherwise checksum value is combination of all fields' checksums, order-independent.
+  - Otherwise, if v implements encoding.BinaryMarshaler or encoding.TextMarshaler: its marshaled form is used to calculate checksum value, e.g. *big.Int or net.IP are hashed by value.
+
+Struct fields can be customized via the `checksum` tag:
+
+  - `checksum:"-"`: the field is excluded from checksum calculation, e.g. volatile fields such as UpdatedAt or caches.
+  - `checksum:"name"`: the field is hashed under "name" instead of its Go name, so that renaming the field does not change the checksum.
+  - `checksum:",unordered"` or `checksum:",set"`: the field, if a slice or an array, is hashed order-independently as a multiset
+    (SliceUnordered) or as a set (SliceSet), see Options.SliceOrder. The options can be combined with a name, e.g. `checksum:"roles,set"`.
 
 Note on special inputs:
 
-  - Checksum of `nil` is a slice where all values are zero.
+  - Checksum of `nil` (or a nil pointer) is a slice where all values are zero.
   - All empty maps have the same checksum, e.g. Checksum(map[string]int{}) == Checksum(map[int]string{}).
   - All empty slices/arrays have the same checksum, e.g. Checksum([]int{}) == Checksum([0]int{}) == Checksum([]string{}) == Checksum([0]string{}).
+
+Values of unsupported kinds (chan, func and unsafe.Pointer) contribute only their types to the checksum.
+Use ChecksumE to detect them.
+
+Checksum(hf, v) is the same as ChecksumWithOptions(hf, v, Options{}).
 */
 func Checksum(hf HashFunc, v interface{}) []byte {
-	if v == nil {
-		return checksumSafe(hf, nil, make(map[uintptr]struct{}))
-	}
-	_, rv := Unwrap(v)
-	return checksumSafe(hf, rv.Interface(), make(map[uintptr]struct{}))
+	return ChecksumWithOptions(hf, v, Options{})
+}
+
+// ChecksumE is similar to Checksum, but returns an UnsupportedKindError if v contains a value of unsupported kind
+// (chan, func or unsafe.Pointer).
+func ChecksumE(hf HashFunc, v interface{}) ([]byte, error) {
+	return ChecksumWithOptionsE(hf, v, Options{})
+}
+
+// UnsupportedKindError is returned when a value of unsupported kind (chan, func or unsafe.Pointer) is encountered.
+type UnsupportedKindError struct {
+	// Path is the semita-style path of the value, e.g. "Handlers[1].callback". Path of the input itself is "".
+	Path string
+	Kind reflect.Kind
+	Type reflect.Type
+}
+
+// Error implements the error interface.
+func (e *UnsupportedKindError) Error() string {
+	return "unsupported kind [" + e.Kind.String() + "] of type [" + e.Type.String() + "] at path [" + e.Path + "]"
 }
 
 const (
@@ -119,98 +182,471 @@ const (
 	markerSliceArray = "0x12"
 )
 
-func checksumSafe(hf HashFunc, v interface{}, visited map[uintptr]struct{}) []byte {
+// checksumContext holds the settings and the state of a checksum calculation.
+type checksumContext struct {
+	hf      HashFunc
+	opts    Options
+	visited map[uintptr]struct{}
+	names   map[string][]byte // cached checksums of markers, type names and field names
+
+	trackPath bool      // if true, path of the value being processed is tracked
+	path      []string  // path segments of the value being processed, e.g. ["Employees", "[1]", "email"]
+	err       error     // the first error encountered
+	node      *TreeNode // if not nil, checksums of child values are recorded as children of this node
+
+	nextSliceOrder SliceOrder // if not SliceOrdered, overrides Options.SliceOrder for the next value (set by struct tags)
+	inWorker       bool       // true if the context belongs to a worker of a parallel calculation, see ChecksumParallel
+}
+
+func newChecksumContext(hf HashFunc, opts Options) *checksumContext {
+	return &checksumContext{hf: hf, opts: opts, visited: make(map[uintptr]struct{}), names: make(map[string][]byte)}
+}
+
+// pathSegment identifies a child value within its parent: a slice/array index, a struct field name or a map key.
+type pathSegment struct {
+	index int
+	name  string
+	key   reflect.Value
+}
+
+func (s pathSegment) String() string {
+	if s.key.IsValid() {
+		return fmt.Sprint(s.key.Interface())
+	}
+	if s.name != "" {
+		return s.name
+	}
+	return "[" + strconv.Itoa(s.index) + "]"
+}
+
+// checksumChild calculates checksum of a child value (e.g. an element of a slice or a field of a struct),
+// identified by the path segment.
+func checksumChild(ctx *checksumContext, segment pathSegment, rv reflect.Value) []byte {
+	if !ctx.trackPath {
+		return checksumValue(ctx, rv)
+	}
+	key := segment.String()
+	ctx.path = append(ctx.path, key)
+	defer func() { ctx.path = ctx.path[:len(ctx.path)-1] }()
+	if parent := ctx.node; parent != nil {
+		node := &TreeNode{Key: key, Path: joinPath(ctx.path)}
+		parent.Children = append(parent.Children, node)
+		ctx.node = node
+		defer func() { ctx.node = parent }()
+		node.Checksum = checksumValue(ctx, rv)
+		return node.Checksum
+	}
+	return checksumValue(ctx, rv)
+}
+
+// checksumMapKey calculates checksum of a map key, identified by the path segment. Map keys are not recorded as tree nodes.
+func checksumMapKey(ctx *checksumContext, segment pathSegment, rv reflect.Value) []byte {
+	if !ctx.trackPath {
+		return checksumValue(ctx, rv)
+	}
+	parent := ctx.node
+	ctx.node = nil
+	ctx.path = append(ctx.path, segment.String())
+	defer func() {
+		ctx.path = ctx.path[:len(ctx.path)-1]
+		ctx.node = parent
+	}()
+	return checksumValue(ctx, rv)
+}
+
+// joinPath joins path segments into a semita-style path, e.g. "Employees[1].email".
+func joinPath(segments []string) string {
+	var sb strings.Builder
+	for _, segment := range segments {
+		if sb.Len() > 0 && !strings.HasPrefix(segment, "[") {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(segment)
+	}
+	return sb.String()
+}
+
+// tagName returns the name part of a `checksum` struct tag, e.g. "name" of `checksum:"name,opt"`.
+func tagName(tag string) string {
+	if i := strings.Index(tag, ","); i >= 0 {
+		return tag[:i]
+	}
+	return tag
+}
+
+// tagSliceOrder returns the slice order specified by options of a `checksum` struct tag, e.g. SliceSet for `checksum:"name,set"`.
+func tagSliceOrder(tag string) SliceOrder {
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		switch strings.TrimSpace(opt) {
+		case "unordered":
+			return SliceUnordered
+		case "set":
+			return SliceSet
+		}
+	}
+	return SliceOrdered
+}
+
+// nilChecksum returns checksum of nil, which is a slice where all values are zero.
+func (ctx *checksumContext) nilChecksum() []byte {
+	result := ctx.hf(nil)
+	for i := range result {
+		result[i] = 0
+	}
+	return result
+}
+
+// scalarChecksum calculates checksum of a scalar value, given its kind and its binary form.
+func (ctx *checksumContext) scalarChecksum(kind reflect.Kind, data []byte) []byte {
+	if ctx.opts.StrictTypes {
+		return ctx.hf(append([]byte(kind.String()+":"), data...))
+	}
+	return ctx.hf(data)
+}
+
+func (ctx *checksumContext) normalizeNumbers() bool {
+	return ctx.opts.NormalizeNumbers || ctx.opts.NumericStrings
+}
+
+// intChecksum calculates checksum of an integer.
+func (ctx *checksumContext) intChecksum(kind reflect.Kind, v int64) []byte {
+	if ctx.normalizeNumbers() {
+		kind = reflect.Int64
+	}
+	return ctx.scalarChecksum(kind, intToBytes(v))
+}
+
+// uintChecksum calculates checksum of an unsigned integer.
+func (ctx *checksumContext) uintChecksum(kind reflect.Kind, v uint64) []byte {
+	if ctx.normalizeNumbers() {
+		if v <= math.MaxInt64 {
+			return ctx.intChecksum(reflect.Int64, int64(v))
+		}
+		kind = reflect.Uint64
+	}
+	return ctx.scalarChecksum(kind, uintToBytes(v))
+}
+
+// floatChecksum calculates checksum of a float. If numbers are normalized, integral floats hash as integers, and float32
+// values hash as the float64 values of their shortest decimal forms (e.g. float32(0.1) hashes as float64(0.1), not
+// as float64(float32(0.1)) = 0.10000000149011612), which is how they are encoded in JSON.
+func (ctx *checksumContext) floatChecksum(kind reflect.Kind, v float64) []byte {
+	if ctx.normalizeNumbers() {
+		if kind == reflect.Float32 && !math.IsInf(v, 0) && !math.IsNaN(v) {
+			v, _ = strconv.ParseFloat(strconv.FormatFloat(v, 'g', -1, 32), 64)
+		}
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return ctx.intChecksum(reflect.Int64, int64(v))
+		}
+		kind = reflect.Float64
+	}
+	return ctx.scalarChecksum(kind, floatToBytes(v))
+}
+
+// numericStringChecksum calculates checksum of a string as the number it represents.
+// It returns nil if the string is not a valid JSON number, or the number is out of range.
+func (ctx *checksumContext) numericStringChecksum(s string) []byte {
+	if !isJSONNumber(s) {
+		return nil
+	}
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return ctx.intChecksum(reflect.Int64, v)
+	}
+	if v, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return ctx.uintChecksum(reflect.Uint64, v)
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return ctx.floatChecksum(reflect.Float64, v)
+	}
+	return nil
+}
+
+// isJSONNumber checks if s is a valid number as per the JSON grammar, e.g. "-1", "2.5" or "1e3".
+func isJSONNumber(s string) bool {
+	i, n := 0, len(s)
+	digits := func() int {
+		start := i
+		for i < n && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		return i - start
+	}
+	if i < n && s[i] == '-' {
+		i++
+	}
+	if i < n && s[i] == '0' {
+		i++
+	} else if digits() == 0 {
+		return false
+	}
+	if i < n && s[i] == '.' {
+		i++
+		if digits() == 0 {
+			return false
+		}
+	}
+	if i < n && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < n && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return i == n
+}
+
+// stringChecksum calculates checksum of a string used internally, e.g. a marker or a field name.
+func (ctx *checksumContext) stringChecksum(s string) []byte {
+	return ctx.hf([]byte(s))
+}
+
+// nameChecksum is similar to stringChecksum, but caches the result for the duration of the calculation.
+// It is used for strings that repeat a lot, such as markers, type names and field names.
+func (ctx *checksumContext) nameChecksum(s string) []byte {
+	if checksum, ok := ctx.names[s]; ok {
+		return checksum
+	}
+	checksum := ctx.stringChecksum(s)
+	ctx.names[s] = checksum
+	return checksum
+}
+
+// folder combines checksums, in order, the same way elements of a slice are combined.
+type folder struct {
+	hf      HashFunc
+	buf     []byte
+	scratch []byte
+}
+
+func newFolder(hf HashFunc) *folder {
+	return &folder{hf: hf, buf: []byte(markerSliceArray)}
+}
+
+func (f *folder) add(checksum []byte) {
+	f.scratch = append(append(f.scratch[:0], f.buf...), checksum...)
+	f.buf = f.hf(f.scratch)
+}
+
+// fold combines a list of checksums, in order, the same way elements of a slice are combined.
+func (ctx *checksumContext) fold(checksums ...[]byte) []byte {
+	f := newFolder(ctx.hf)
+	for _, checksum := range checksums {
+		f.add(checksum)
+	}
+	return f.buf
+}
+
+// checksumUnordered calculates checksum of a slice or an array, order-independent: element checksums are combined the same way
+// map entries are. If set is true, duplicate elements (elements having the same checksum) are counted once.
+func checksumUnordered(ctx *checksumContext, plan *typePlan, rv reflect.Value, set bool) []byte {
+	markers := []string{markerSliceArray}
+	if ctx.opts.IncludeTypeNames {
+		markers = append(markers, plan.typeName)
+	}
+	var checksums [][]byte
+	if ctx.parallel(rv.Len()) {
+		checksums = parallelElements(ctx, rv)
+	} else {
+		checksums = make([][]byte, 0, rv.Len())
+		for i, n := 0, rv.Len(); i < n; i++ {
+			checksums = append(checksums, checksumChild(ctx, pathSegment{index: i}, rv.Index(i)))
+		}
+	}
+	if set {
+		checksums = uniqueChecksums(checksums)
+	}
+	return ctx.foldSorted(markers, checksums)
+}
+
+// uniqueChecksums sorts a list of checksums and removes duplicates from it.
+func uniqueChecksums(checksums [][]byte) [][]byte {
+	sort.Sort(byteSlices(checksums))
+	result := checksums[:0]
+	for i, checksum := range checksums {
+		if i == 0 || !bytes.Equal(checksum, checksums[i-1]) {
+			result = append(result, checksum)
+		}
+	}
+	return result
+}
+
+type byteSlices [][]byte
+
+func (s byteSlices) Len() int           { return len(s) }
+func (s byteSlices) Less(i, j int) bool { return bytes.Compare(s[i], s[j]) < 0 }
+func (s byteSlices) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// foldSorted combines a list of checksums, order-independent, following the leading marker checksums.
+//
+// Checksums are sorted by their hex-encoded forms, and each hex-encoded form is hashed before being combined.
+// Note: hex encoding preserves byte order, so sorting the raw checksums gives the same order.
+func (ctx *checksumContext) foldSorted(markers []string, checksums [][]byte) []byte {
+	sort.Sort(byteSlices(checksums))
+	f := newFolder(ctx.hf)
+	for _, marker := range markers {
+		f.add(ctx.nameChecksum(marker))
+	}
+	var hexBuf []byte
+	for _, checksum := range checksums {
+		if n := hex.EncodedLen(len(checksum)); cap(hexBuf) < n {
+			hexBuf = make([]byte, n)
+		} else {
+			hexBuf = hexBuf[:n]
+		}
+		hex.Encode(hexBuf, checksum)
+		f.add(ctx.hf(hexBuf))
+	}
+	return f.buf
+}
+
+func checksumSafe(ctx *checksumContext, v interface{}) []byte {
 	if v == nil {
-		result := hf(nil)
-		for i := range result {
-			result[i] = 0
+		return ctx.nilChecksum()
+	}
+	return checksumValue(ctx, reflect.ValueOf(v))
+}
+
+func checksumValue(ctx *checksumContext, rv reflect.Value) []byte {
+	sliceOrder := ctx.opts.SliceOrder
+	if ctx.nextSliceOrder != SliceOrdered {
+		sliceOrder, ctx.nextSliceOrder = ctx.nextSliceOrder, SliceOrdered
+	}
+	if !rv.IsValid() || (rv.Kind() == reflect.Interface && rv.IsNil()) {
+		return ctx.nilChecksum()
+	}
+	// unwrap pointers and interfaces, prv is the last pointer on the way
+	var prv reflect.Value
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.Kind() == reflect.Ptr {
+			prv = rv
 		}
-		return result
+		rv = rv.Elem()
 	}
-	prv, rv := Unwrap(v)
-	var ptr *uintptr
+
+	var ptr uintptr
+	tracked := false
 	switch rv.Kind() {
 	case reflect.Map, reflect.Slice:
-		ptrTemp := rv.Pointer()
-		ptr = &ptrTemp
+		ptr, tracked = rv.Pointer(), true
 	default:
-		if prv.IsValid() && !prv.IsZero() && !prv.IsNil() {
-			ptrTemp := prv.Pointer()
-			ptr = &ptrTemp
+		if prv.IsValid() && !prv.IsNil() {
+			ptr, tracked = prv.Pointer(), true
 		}
 	}
-	if ptr != nil {
-		if _, ok := visited[*ptr]; ok {
-			return checksumSafe(hf, nil, visited)
+	if tracked {
+		if _, ok := ctx.visited[ptr]; ok {
+			return ctx.nilChecksum()
+		}
+		ctx.visited[ptr] = struct{}{}
+		defer delete(ctx.visited, ptr)
+	}
+
+	if !rv.IsValid() {
+		return nil
+	}
+	plan := typePlanOf(rv.Type())
+	if plan.hasCustom {
+		if checksum, ok := customChecksum(ctx, plan, prv, rv); ok {
+			return checksum
 		}
-		visited[*ptr] = struct{}{}
-		defer delete(visited, *ptr)
 	}
 
 	switch rv.Kind() {
 	case reflect.Bool:
-		return hf(boolToBytes(rv.Bool()))
+		return ctx.scalarChecksum(rv.Kind(), boolToBytes(rv.Bool()))
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return hf(intToBytes(rv.Int()))
-	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return hf(uintToBytes(rv.Uint()))
+		if plan.isDuration {
+			return ctx.durationChecksum(time.Duration(rv.Int()))
+		}
+		return ctx.intChecksum(rv.Kind(), rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return ctx.uintChecksum(rv.Kind(), rv.Uint())
 	case reflect.Float32, reflect.Float64:
-		return hf(floatToBytes(rv.Float()))
+		return ctx.floatChecksum(rv.Kind(), rv.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := rv.Complex()
+		return ctx.scalarChecksum(rv.Kind(), append(floatToBytes(real(c)), floatToBytes(imag(c))...))
 	case reflect.String:
-		return hf([]byte(rv.String()))
+		if ctx.opts.NumericStrings || (plan.isJSONNumber && ctx.opts.NormalizeNumbers) {
+			if checksum := ctx.numericStringChecksum(rv.String()); checksum != nil {
+				return checksum
+			}
+		}
+		return ctx.scalarChecksum(rv.Kind(), []byte(rv.String()))
 	case reflect.Array, reflect.Slice:
-		buf := []byte(markerSliceArray)
+		if ctx.opts.DistinguishNilEmpty && rv.Kind() == reflect.Slice && rv.IsNil() {
+			return ctx.nilChecksum()
+		}
+		if sliceOrder != SliceOrdered {
+			return checksumUnordered(ctx, plan, rv, sliceOrder == SliceSet)
+		}
+		f := newFolder(ctx.hf)
+		if ctx.opts.IncludeTypeNames {
+			f.add(ctx.nameChecksum(plan.typeName))
+		}
+		if ctx.parallel(rv.Len()) {
+			for _, checksum := range parallelElements(ctx, rv) {
+				f.add(checksum)
+			}
+			return f.buf
+		}
 		for i, n := 0, rv.Len(); i < n; i++ {
-			buf = hf(append(buf, checksumSafe(hf, rv.Index(i).Interface(), visited)...))
+			f.add(checksumChild(ctx, pathSegment{index: i}, rv.Index(i)))
 		}
-		return buf
+		return f.buf
 	case reflect.Map:
-		temp := make([]string, 0)
+		if ctx.opts.DistinguishNilEmpty && rv.IsNil() {
+			return ctx.nilChecksum()
+		}
+		markers := []string{markerMap}
+		if ctx.opts.IncludeTypeNames {
+			markers = append(markers, plan.typeName)
+		}
+		if ctx.parallel(rv.Len()) {
+			return ctx.foldSorted(markers, parallelEntries(ctx, rv))
+		}
+		checksums := make([][]byte, 0, rv.Len())
 		for iter := rv.MapRange(); iter.Next(); {
 			// field-name is taking into account
-			fieldChecksum := checksumSafe(hf, []interface{}{iter.Key().Interface(), iter.Value().Interface()}, visited)
-			temp = append(temp, fmt.Sprintf("%x", fieldChecksum))
+			key := iter.Key()
+			segment := pathSegment{key: key}
+			checksums = append(checksums, ctx.fold(checksumMapKey(ctx, segment, key), checksumChild(ctx, segment, iter.Value())))
 		}
-		sort.Strings(temp)
-		return checksumSafe(hf, append([]string{markerMap}, temp...), visited)
-	case reflect.Struct:
-		m := rv.MethodByName("Checksum")
-		if !m.IsValid() && prv.IsValid() {
-			m = prv.MethodByName("Checksum")
-		}
-		if m.IsValid() && m.Type().NumIn() == 0 {
-			// struct has matched method Checksum
-			temp := make([]interface{}, 0)
-			result := m.Call(nil)
-			for _, vtemp := range result {
-				temp = append(temp, vtemp.Interface())
-			}
-			return checksumSafe(hf, append([]interface{}{markerStruct, rv.Type().String()}, temp...), visited)
+		if ctx.node != nil {
+			sortTreeNodes(ctx.node.Children)
 		}
-
-		if rv.Type() == reflect.TypeOf(time.Time{}) {
-			return checksumSafe(hf, append([]interface{}{markerStruct, rv.Type().String()}, rv.Interface().(time.Time).UnixNano()), visited)
+		return ctx.foldSorted(markers, checksums)
+	case reflect.Struct:
+		if plan.hasUnexported && !ctx.opts.IgnoreUnexported && !rv.CanAddr() {
+			// unexported fields are read via their addresses, hence an addressable copy of the struct is needed
+			temp := reflect.New(rv.Type()).Elem()
+			temp.Set(rv)
+			rv = temp
 		}
-
-		temp := make([]string, 0)
-		for i, n := 0, rv.NumField(); i < n; i++ {
+		checksums := make([][]byte, 0, len(plan.fields))
+		for _, field := range plan.fields {
 			// field-name is taking into account
-			fieldName := rv.Type().Field(i).Name
-			fieldValue := rv.Field(i)
-			if !isExportedField(fieldName) {
+			fieldValue := rv.Field(field.index)
+			if !field.exported {
+				if ctx.opts.IgnoreUnexported {
+					continue
+				}
 				// handle unexported field
-				rv2 := reflect.New(rv.Type()).Elem()
-				rv2.Set(rv)
-				fieldValue = rv2.Field(i)
 				fieldValue = reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr())).Elem()
 			}
-			fieldChecksum := checksumSafe(hf, []interface{}{fieldName, fieldValue.Interface()}, visited)
-			temp = append(temp, fmt.Sprintf("%x", fieldChecksum))
+			ctx.nextSliceOrder = field.sliceOrder
+			checksums = append(checksums, ctx.fold(ctx.nameChecksum(field.name), checksumChild(ctx, pathSegment{name: field.name}, fieldValue)))
 		}
-		sort.Strings(temp)
-		return checksumSafe(hf, append([]string{markerStruct, rv.Type().String()}, temp...), visited)
+		return ctx.foldSorted([]string{markerStruct, plan.typeName}, checksums)
 	default:
-		return nil
+		// chan, func and unsafe.Pointer: only type of the value contributes to the checksum
+		if ctx.err == nil {
+			ctx.err = &UnsupportedKindError{Path: joinPath(ctx.path), Kind: rv.Kind(), Type: rv.Type()}
+		}
+		return ctx.fold(ctx.nameChecksum(markerStruct), ctx.nameChecksum(plan.typeName))
 	}
 }
 
@@ -238,3 +674,28 @@ func Sha256Checksum(v interface{}) []byte {
 func Sha512Checksum(v interface{}) []byte {
 	return Checksum(Sha512HashFunc, v)
 }
+
+// Sha512_256Checksum is shortcut of Checksum(Sha512_256HashFunc, v).
+func Sha512_256Checksum(v interface{}) []byte {
+	return Checksum(Sha512_256HashFunc, v)
+}
+
+// Crc64Checksum is shortcut of Checksum(Crc64HashFunc, v).
+func Crc64Checksum(v interface{}) []byte {
+	return Checksum(Crc64HashFunc, v)
+}
+
+// Fnv1a64Checksum is shortcut of Checksum(Fnv1a64HashFunc, v).
+func Fnv1a64Checksum(v interface{}) []byte {
+	return Checksum(Fnv1a64HashFunc, v)
+}
+
+// Fnv1a128Checksum is shortcut of Checksum(Fnv1a128HashFunc, v).
+func Fnv1a128Checksum(v interface{}) []byte {
+	return Checksum(Fnv1a128HashFunc, v)
+}
+
+// XxHash64Checksum is shortcut of Checksum(XxHash64HashFunc, v).
+func XxHash64Checksum(v interface{}) []byte {
+	return Checksum(XxHash64HashFunc, v)
+}
@@ -0,0 +1,26 @@
+//go:build go1.24
+// +build go1.24
+
+package checksum
+
+import (
+	"crypto/sha3"
+)
+
+// Sha3_256HashFunc is a HashFunc that calculates hash value using SHA3-256.
+//
+// Note: available with Go 1.24+, as package crypto/sha3 was added to the standard library in Go 1.24.
+var Sha3_256HashFunc HashFunc = func(input []byte) []byte {
+	return hashFunc(sha3.New256(), input)
+}
+
+// Sha3_256Checksum is shortcut of Checksum(Sha3_256HashFunc, v).
+//
+// Note: available with Go 1.24+.
+func Sha3_256Checksum(v interface{}) []byte {
+	return Checksum(Sha3_256HashFunc, v)
+}
+
+func init() {
+	_ = RegisterAlgorithm(AlgSha3_256, Sha3_256HashFunc)
+}
@@ -0,0 +1,28 @@
+//go:build go1.24
+// +build go1.24
+
+package checksum
+
+import (
+	"crypto/sha3"
+	"fmt"
+	"hash"
+	"testing"
+)
+
+func init() {
+	nameList = append(nameList, "SHA3_256")
+	hfList = append(hfList, Sha3_256HashFunc)
+	csfList = append(csfList, Sha3_256Checksum)
+	newHashList = append(newHashList, func() hash.Hash { return sha3.New256() })
+	algList = append(algList, AlgSha3_256)
+}
+
+func TestHashFunc_Sha3(t *testing.T) {
+	testName := "TestHashFunc_Sha3"
+	input := []byte("The quick brown fox jumps over the lazy dog")
+	expected := "69070dda01975c8c120c3aada1b282394e7f032fa9cf32f4cb2259a0897dfc04"
+	if checksum := fmt.Sprintf("%x", Sha3_256HashFunc(input)); checksum != expected {
+		t.Fatalf("%s failed: expected %s but received %s", testName, expected, checksum)
+	}
+}
@@ -2,15 +2,20 @@ package checksum
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
+	"unsafe"
 )
 
-var nameList = []string{"CRC32", "MD5", "SHA1", "SHA256", "SHA512"}
-var hfList = []HashFunc{Crc32HashFunc, Md5HashFunc, Sha1HashFunc, Sha256HashFunc, Sha512HashFunc}
-var csfList = []func(interface{}) []byte{Crc32Checksum, Md5Checksum, Sha1Checksum, Sha256Checksum, Sha512Checksum}
+var nameList = []string{"CRC32", "MD5", "SHA1", "SHA256", "SHA512", "SHA512_256", "CRC64", "FNV1a64", "FNV1a128", "XXH64"}
+var hfList = []HashFunc{Crc32HashFunc, Md5HashFunc, Sha1HashFunc, Sha256HashFunc, Sha512HashFunc, Sha512_256HashFunc, Crc64HashFunc, Fnv1a64HashFunc, Fnv1a128HashFunc, XxHash64HashFunc}
+var csfList = []func(interface{}) []byte{Crc32Checksum, Md5Checksum, Sha1Checksum, Sha256Checksum, Sha512Checksum, Sha512_256Checksum, Crc64Checksum, Fnv1a64Checksum, Fnv1a128Checksum, XxHash64Checksum}
 
 func TestChecksum_nil(t *testing.T) {
 	testName := "TestChecksum_nil"
@@ -1074,3 +1079,520 @@ func TestChecksum_TwoStructs(t *testing.T) {
 		})
 	}
 }
+
+func TestChecksum_StructTagIgnore(t *testing.T) {
+	testName := "TestChecksum_StructTagIgnore"
+	type MyStructTag struct {
+		S         string
+		N         int
+		UpdatedAt time.Time `checksum:"-"`
+		cache     []byte    `checksum:"-"`
+	}
+	now := time.Now()
+	v1 := MyStructTag{S: "string", N: 1, UpdatedAt: now, cache: []byte("cache")}
+	v2 := MyStructTag{S: "string", N: 1, UpdatedAt: now.Add(time.Hour)}
+	v3 := MyStructTag{S: "string", N: 2, UpdatedAt: now}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, &v2))
+			checksum3 := fmt.Sprintf("%x", Checksum(hf, v3))
+			if checksum1 != checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+			if checksum1 == checksum3 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v3, checksum3)
+			}
+		})
+	}
+}
+
+func TestChecksum_StructTagVsUntagged(t *testing.T) {
+	testName := "TestChecksum_StructTagVsUntagged"
+	// MyStructLogical is declared in both functions, so that both versions share the same type name
+	tagged := func() interface{} {
+		type MyStructLogical struct {
+			FullName  string `checksum:"Name"`
+			Years     int    `checksum:"Age,omitempty"`
+			Email     string
+			UpdatedAt time.Time `checksum:"-"`
+		}
+		return MyStructLogical{FullName: "Thanh Nguyen", Years: 30, Email: "me@domain.com", UpdatedAt: time.Now()}
+	}
+	untagged := func() interface{} {
+		type MyStructLogical struct {
+			Name  string
+			Age   int
+			Email string
+		}
+		return MyStructLogical{Name: "Thanh Nguyen", Age: 30, Email: "me@domain.com"}
+	}
+	v1, v2 := tagged(), untagged()
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, v2))
+			if checksum1 != checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+		})
+	}
+}
+
+func TestChecksum_StructTagUnordered(t *testing.T) {
+	testName := "TestChecksum_StructTagUnordered"
+	type MyStructRoles struct {
+		Name   string
+		Roles  []string  `checksum:"roles,set"`
+		Tags   *[]string `checksum:",unordered"`
+		Matrix [][]int   `checksum:",unordered"`
+	}
+	tags1, tags2, tags3 := []string{"x", "y", "y"}, []string{"y", "x", "y"}, []string{"x", "y"}
+	v1 := MyStructRoles{Name: "n", Roles: []string{"admin", "user"}, Tags: &tags1, Matrix: [][]int{{1, 2}, {3, 4}}}
+	v2 := MyStructRoles{Name: "n", Roles: []string{"user", "admin", "user"}, Tags: &tags2, Matrix: [][]int{{3, 4}, {1, 2}}}
+	v3 := MyStructRoles{Name: "n", Roles: []string{"admin", "user"}, Tags: &tags3, Matrix: [][]int{{1, 2}, {3, 4}}}
+	v4 := MyStructRoles{Name: "n", Roles: []string{"admin", "user"}, Tags: &tags1, Matrix: [][]int{{2, 1}, {3, 4}}}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, v2))
+			if checksum1 != checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+			// duplicates count in unordered slices
+			if checksum3 := fmt.Sprintf("%x", Checksum(hf, v3)); checksum1 == checksum3 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v3, checksum3)
+			}
+			// nested slices are still ordered
+			if checksum4 := fmt.Sprintf("%x", Checksum(hf, v4)); checksum1 == checksum4 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v4, checksum4)
+			}
+		})
+	}
+}
+
+func TestChecksum_StructTagDash(t *testing.T) {
+	testName := "TestChecksum_StructTagDash"
+	type MyStructDash1 struct {
+		S string `checksum:"-,"`
+	}
+	type MyStructDash2 struct {
+		S string `checksum:"-"`
+	}
+	v1 := MyStructDash1{S: "a string"}
+	v2 := MyStructDash1{S: "another string"}
+	v3 := MyStructDash2{S: "a string"}
+	v4 := MyStructDash2{S: "another string"}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, v2))
+			checksum3 := fmt.Sprintf("%x", Checksum(hf, v3))
+			checksum4 := fmt.Sprintf("%x", Checksum(hf, v4))
+			if checksum1 == checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+			if checksum3 != checksum4 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v3, checksum3, v4, checksum4)
+			}
+		})
+	}
+}
+
+func TestChecksum_NilPointer(t *testing.T) {
+	testName := "TestChecksum_NilPointer"
+	var p1 *int
+	var p2 *MyStructPubPriv
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksumNil := fmt.Sprintf("%x", Checksum(hf, nil))
+			if checksum := fmt.Sprintf("%x", Checksum(hf, p1)); checksum != checksumNil {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(nil)=%s", testName+"/"+name, p1, checksum, checksumNil)
+			}
+			if checksum := fmt.Sprintf("%x", Checksum(hf, &p2)); checksum != checksumNil {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(nil)=%s", testName+"/"+name, &p2, checksum, checksumNil)
+			}
+		})
+	}
+}
+
+func TestChecksum_Complex(t *testing.T) {
+	testName := "TestChecksum_Complex"
+	v1 := complex64(1 + 2i)
+	v2 := complex128(1 + 2i)
+	v3 := complex128(2 + 1i)
+	v4 := complex128(1)
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, &v2))
+			checksum3 := fmt.Sprintf("%x", Checksum(hf, v3))
+			checksum4 := fmt.Sprintf("%x", Checksum(hf, v4))
+			if checksum1 != checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+			if checksum1 == checksum3 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v3, checksum3)
+			}
+			if checksum := fmt.Sprintf("%x", Checksum(hf, float64(1))); checksum == checksum4 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v4, checksum4, float64(1), checksum)
+			}
+			if _, err := ChecksumE(hf, v1); err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+name, err)
+			}
+		})
+	}
+}
+
+type MyStructHandlers struct {
+	Name     string
+	Handlers []interface{}
+}
+
+func TestChecksum_UnsupportedKind(t *testing.T) {
+	testName := "TestChecksum_UnsupportedKind"
+	ch := make(chan int)
+	testData := []struct {
+		input interface{}
+		path  string
+		kind  reflect.Kind
+	}{
+		{func() {}, "", reflect.Func},
+		{&ch, "", reflect.Chan},
+		{[]interface{}{1, "a", unsafe.Pointer(&ch)}, "[2]", reflect.UnsafePointer},
+		{map[string]interface{}{"a": 1, "b": map[string]interface{}{"c": ch}}, "b.c", reflect.Chan},
+		{MyStructHandlers{Name: "name", Handlers: []interface{}{"a", func() {}}}, "Handlers[1]", reflect.Func},
+		{&MyStructHandlers{Handlers: []interface{}{map[int]interface{}{1: []interface{}{ch}}}}, "Handlers[0].1[0]", reflect.Chan},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, td := range testData {
+				checksum, err := ChecksumE(hf, td.input)
+				if checksum != nil || err == nil {
+					t.Fatalf("%s failed: expected error for input %#v", testName+"/"+name, td.input)
+				}
+				e, ok := err.(*UnsupportedKindError)
+				if !ok || e.Path != td.path || e.Kind != td.kind {
+					t.Fatalf("%s failed: expected error at path [%s] of kind [%s] but received %#v", testName+"/"+name, td.path, td.kind, err)
+				}
+				if len(Checksum(hf, td.input)) == 0 {
+					t.Fatalf("%s failed: Checksum(%#v) is nil/empty", testName+"/"+name, td.input)
+				}
+			}
+
+			v1 := MyStructHandlers{Name: "name", Handlers: []interface{}{func() {}}}
+			v2 := MyStructHandlers{Name: "name", Handlers: []interface{}{make(chan int)}}
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, v2))
+			if checksum1 == checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+		})
+	}
+}
+
+func TestChecksumE(t *testing.T) {
+	testName := "TestChecksumE"
+	now := time.Now()
+	vList := []interface{}{nil, 1, uintptr(2), "a string", now, []int{1, 2}, map[string]interface{}{"a": []interface{}{1, "b"}},
+		MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4}}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, v := range vList {
+				checksum, err := ChecksumE(hf, v)
+				if err != nil {
+					t.Fatalf("%s failed: %s", testName+"/"+name, err)
+				}
+				if expected := Checksum(hf, v); !reflect.DeepEqual(checksum, expected) {
+					t.Fatalf("%s failed: expected %x but received %x", testName+"/"+name, expected, checksum)
+				}
+			}
+		})
+	}
+}
+
+func TestHashFunc(t *testing.T) {
+	testName := "TestHashFunc"
+	input := []byte("The quick brown fox jumps over the lazy dog")
+	expected := map[string]string{
+		"CRC32":      "414fa339",
+		"MD5":        "9e107d9d372bb6826bd81d3542a419d6",
+		"SHA1":       "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12",
+		"SHA256":     "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592",
+		"SHA512":     "07e547d9586f6a73f73fbac0435ed76951218fb7d0c8d788a309d785436bbb642e93a252a954f23912547d1e8a3b5ed6e1bfd7097821233fa0538f3db854fee6",
+		"SHA512_256": "dd9d67b371519c339ed8dbd25af90e976a1eeefd4ad3d889005e532fc5bef04d",
+		"CRC64":      "5b5eb8c2e54aa1c4",
+		"FNV1a64":    "f3f9b7f5e7e47110",
+		"FNV1a128":   "68cce4cd885ea04239f02af30e297870",
+		"XXH64":      "0b242d361fda71bc",
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			exp, ok := expected[name]
+			if !ok {
+				t.Skipf("%s skipped: no test vector", testName+"/"+name)
+			}
+			if checksum := fmt.Sprintf("%x", hfList[i](input)); checksum != exp {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, exp, checksum)
+			}
+		})
+	}
+}
+
+func TestHmacHashFunc(t *testing.T) {
+	testName := "TestHmacHashFunc"
+	key := []byte("key")
+	hf := HmacHashFunc(sha256.New, key)
+	key[0] = 'K' // changing the key after creating the HashFunc must not affect it
+	input := []byte("The quick brown fox jumps over the lazy dog")
+	expected := "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if checksum := fmt.Sprintf("%x", hf(input)); checksum != expected {
+		t.Fatalf("%s failed: expected %s but received %s", testName, expected, checksum)
+	}
+
+	v := map[string]interface{}{"amount": 100, "to": "account"}
+	checksum1 := fmt.Sprintf("%x", Checksum(HmacHashFunc(sha512.New, []byte("key1")), v))
+	checksum2 := fmt.Sprintf("%x", Checksum(HmacHashFunc(sha512.New, []byte("key2")), v))
+	checksum3 := fmt.Sprintf("%x", Checksum(Sha512HashFunc, v))
+	if checksum1 == checksum2 || checksum1 == checksum3 {
+		t.Fatalf("%s failed: checksums calculated with different keys must be different: %s / %s / %s", testName, checksum1, checksum2, checksum3)
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func isExportedField(fieldName string) bool {
+	return len(fieldName) > 0 && string(fieldName[0]) == strings.ToUpper(string(fieldName[0]))
+}
+
+// legacyChecksum is the implementation of Checksum as of v1.1.1, which reflects through values on every call.
+// It is kept as the reference to verify that Checksum produces byte-identical results, and for benchmarks.
+func legacyChecksum(hf HashFunc, v interface{}) []byte {
+	if v == nil {
+		return legacyChecksumSafe(hf, nil, make(map[uintptr]struct{}))
+	}
+	_, rv := Unwrap(v)
+	return legacyChecksumSafe(hf, rv.Interface(), make(map[uintptr]struct{}))
+}
+
+func legacyChecksumSafe(hf HashFunc, v interface{}, visited map[uintptr]struct{}) []byte {
+	if v == nil {
+		result := hf(nil)
+		for i := range result {
+			result[i] = 0
+		}
+		return result
+	}
+	prv, rv := Unwrap(v)
+	var ptr *uintptr
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice:
+		ptrTemp := rv.Pointer()
+		ptr = &ptrTemp
+	default:
+		if prv.IsValid() && !prv.IsZero() && !prv.IsNil() {
+			ptrTemp := prv.Pointer()
+			ptr = &ptrTemp
+		}
+	}
+	if ptr != nil {
+		if _, ok := visited[*ptr]; ok {
+			return legacyChecksumSafe(hf, nil, visited)
+		}
+		visited[*ptr] = struct{}{}
+		defer delete(visited, *ptr)
+	}
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		return hf(boolToBytes(rv.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return hf(intToBytes(rv.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return hf(uintToBytes(rv.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return hf(floatToBytes(rv.Float()))
+	case reflect.String:
+		return hf([]byte(rv.String()))
+	case reflect.Array, reflect.Slice:
+		buf := []byte(markerSliceArray)
+		for i, n := 0, rv.Len(); i < n; i++ {
+			buf = hf(append(buf, legacyChecksumSafe(hf, rv.Index(i).Interface(), visited)...))
+		}
+		return buf
+	case reflect.Map:
+		temp := make([]string, 0)
+		for iter := rv.MapRange(); iter.Next(); {
+			// field-name is taking into account
+			fieldChecksum := legacyChecksumSafe(hf, []interface{}{iter.Key().Interface(), iter.Value().Interface()}, visited)
+			temp = append(temp, fmt.Sprintf("%x", fieldChecksum))
+		}
+		sort.Strings(temp)
+		return legacyChecksumSafe(hf, append([]string{markerMap}, temp...), visited)
+	case reflect.Struct:
+		m := rv.MethodByName("Checksum")
+		if !m.IsValid() && prv.IsValid() {
+			m = prv.MethodByName("Checksum")
+		}
+		if m.IsValid() && m.Type().NumIn() == 0 {
+			// struct has matched method Checksum
+			temp := make([]interface{}, 0)
+			result := m.Call(nil)
+			for _, vtemp := range result {
+				temp = append(temp, vtemp.Interface())
+			}
+			return legacyChecksumSafe(hf, append([]interface{}{markerStruct, rv.Type().String()}, temp...), visited)
+		}
+
+		if rv.Type() == reflect.TypeOf(time.Time{}) {
+			return legacyChecksumSafe(hf, append([]interface{}{markerStruct, rv.Type().String()}, rv.Interface().(time.Time).UnixNano()), visited)
+		}
+
+		temp := make([]string, 0)
+		for i, n := 0, rv.NumField(); i < n; i++ {
+			// field-name is taking into account
+			fieldName := rv.Type().Field(i).Name
+			fieldValue := rv.Field(i)
+			if !isExportedField(fieldName) {
+				// handle unexported field
+				rv2 := reflect.New(rv.Type()).Elem()
+				rv2.Set(rv)
+				fieldValue = rv2.Field(i)
+				fieldValue = reflect.NewAt(fieldValue.Type(), unsafe.Pointer(fieldValue.UnsafeAddr())).Elem()
+			}
+			fieldChecksum := legacyChecksumSafe(hf, []interface{}{fieldName, fieldValue.Interface()}, visited)
+			temp = append(temp, fmt.Sprintf("%x", fieldChecksum))
+		}
+		sort.Strings(temp)
+		return legacyChecksumSafe(hf, append([]string{markerStruct, rv.Type().String()}, temp...), visited)
+	default:
+		return nil
+	}
+}
+
+type MyStructBenchmark struct {
+	ID        int64
+	Name      string
+	Email     string
+	Score     float64
+	Active    bool
+	Tags      []string
+	Props     map[string]interface{}
+	CreatedAt time.Time
+	Manager   *MyStructBenchmark
+	internal  string
+	counter   int
+}
+
+func newBenchmarkStruct(i int) MyStructBenchmark {
+	manager := &MyStructBenchmark{ID: 1, Name: "manager", internal: "manager", CreatedAt: time.Unix(1700000000, 0)}
+	return MyStructBenchmark{
+		ID:        int64(i),
+		Name:      fmt.Sprintf("name-%d", i),
+		Email:     fmt.Sprintf("user%d@domain.com", i),
+		Score:     float64(i) * 1.5,
+		Active:    i%2 == 0,
+		Tags:      []string{"tag1", "tag2", fmt.Sprintf("tag-%d", i)},
+		Props:     map[string]interface{}{"a": i, "b": "value", "c": []int{1, 2, 3}},
+		CreatedAt: time.Unix(1700000000+int64(i), 0),
+		Manager:   manager,
+		internal:  fmt.Sprintf("internal-%d", i),
+		counter:   i,
+	}
+}
+
+func TestChecksum_LegacyCompatibility(t *testing.T) {
+	testName := "TestChecksum_LegacyCompatibility"
+	now := time.Now()
+	v1 := []interface{}{1, nil, 2.3, true}
+	v1[1] = v1
+	v2 := Node{Value: "node"}
+	v2.Next = &v2
+	vList := []interface{}{
+		nil, true, 1, int8(-2), uint16(3), 4.5, float32(6.7), "a string", now, &now,
+		[]int{}, []int{1, 2, 3}, [2]string{"a", "b"}, []byte("bytes"), []interface{}{nil, []int{}, map[string]int{}},
+		map[string]int{}, map[string]interface{}{"a": 1, "b": []interface{}{"x", nil}, "c": map[int]string{1: "one"}, "d": &now},
+		struct{}{}, newBenchmarkStruct(1), &MyStructPubPrivPointer{}, MyStructCustom1{S: "s", N: 2}, &MyStructCustom2{S: "s"},
+		MyStructAllPublic{S: "s", A: []interface{}{1, &now}, M: map[string]interface{}{"t": now}, TP: &now},
+		v1, &v1, v2, &v2, []MyStructBenchmark{newBenchmarkStruct(1), newBenchmarkStruct(2)},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, v := range vList {
+				expected := legacyChecksum(hf, v)
+				if checksum := Checksum(hf, v); !reflect.DeepEqual(checksum, expected) {
+					t.Fatalf("%s failed for input %#v: expected %x but received %x", testName+"/"+name, v, expected, checksum)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkChecksum_Struct(b *testing.B) {
+	v := newBenchmarkStruct(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Checksum(Sha256HashFunc, v)
+	}
+}
+
+func BenchmarkLegacyChecksum_Struct(b *testing.B) {
+	v := newBenchmarkStruct(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		legacyChecksum(Sha256HashFunc, v)
+	}
+}
+
+func BenchmarkChecksum_SliceOfStructs(b *testing.B) {
+	v := make([]MyStructBenchmark, 100)
+	for i := range v {
+		v[i] = newBenchmarkStruct(i)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Checksum(Sha256HashFunc, v)
+	}
+}
+
+func BenchmarkLegacyChecksum_SliceOfStructs(b *testing.B) {
+	v := make([]MyStructBenchmark, 100)
+	for i := range v {
+		v[i] = newBenchmarkStruct(i)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		legacyChecksum(Sha256HashFunc, v)
+	}
+}
+
+func BenchmarkChecksum_Map(b *testing.B) {
+	v := make(map[string]interface{}, 1000)
+	for i := 0; i < 1000; i++ {
+		v[fmt.Sprintf("key-%d", i)] = i
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Checksum(Sha256HashFunc, v)
+	}
+}
+
+func BenchmarkLegacyChecksum_Map(b *testing.B) {
+	v := make(map[string]interface{}, 1000)
+	for i := 0; i < 1000; i++ {
+		v[fmt.Sprintf("key-%d", i)] = i
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		legacyChecksum(Sha256HashFunc, v)
+	}
+}
@@ -0,0 +1,85 @@
+package checksum
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// Checksummer is implemented by types that calculate their own checksums.
+//
+// The returned value is combined with the type name to calculate the final checksum,
+// so two different types never share checksums even if their Checksum methods return the same value.
+type Checksummer interface {
+	Checksum(hf HashFunc) []byte
+}
+
+var (
+	typeChecksummer     = reflect.TypeOf((*Checksummer)(nil)).Elem()
+	typeBinaryMarshaler = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	typeTextMarshaler   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeTime            = reflect.TypeOf(time.Time{})
+	typeJSONNumber      = reflect.TypeOf(json.Number(""))
+	typeDuration        = reflect.TypeOf(time.Duration(0))
+)
+
+// implementer returns the value that implements an interface, either rv itself or a pointer to it.
+func implementer(kind implKind, prv, rv reflect.Value) reflect.Value {
+	if kind == implValue {
+		return rv
+	}
+	if prv.IsValid() && prv.Type().Elem() == rv.Type() {
+		return prv
+	}
+	if rv.CanAddr() {
+		return rv.Addr()
+	}
+	ptr := reflect.New(rv.Type())
+	ptr.Elem().Set(rv)
+	return ptr
+}
+
+// customChecksum calculates checksum of values that define their own canonical forms, in order of precedence:
+// Checksummer, structs with the legacy "Checksum()" method, time.Time (and named types of time.Time), encoding.BinaryMarshaler and encoding.TextMarshaler.
+//
+// The second return value is false if v does not define its own canonical form.
+func customChecksum(ctx *checksumContext, plan *typePlan, prv, rv reflect.Value) ([]byte, bool) {
+	if plan.checksummer != implNone {
+		checksum := implementer(plan.checksummer, prv, rv).Interface().(Checksummer).Checksum(ctx.hf)
+		return ctx.fold(ctx.nameChecksum(markerStruct), ctx.nameChecksum(plan.typeName), checksum), true
+	}
+
+	if plan.legacyMethod >= 0 {
+		// struct has matched method Checksum: kept for backward compatibility, new code should implement Checksummer instead
+		var m reflect.Value
+		if !plan.legacyOnPointer {
+			m = rv.Method(plan.legacyMethod)
+		} else if prv.IsValid() && prv.Type().Elem() == rv.Type() {
+			m = prv.Method(plan.legacyMethod)
+		}
+		if m.IsValid() {
+			checksums := [][]byte{ctx.nameChecksum(markerStruct), ctx.nameChecksum(plan.typeName)}
+			for _, vtemp := range m.Call(nil) {
+				checksums = append(checksums, checksumSafe(ctx, vtemp.Interface()))
+			}
+			return ctx.fold(checksums...), true
+		}
+	}
+
+	if plan.isTime {
+		return ctx.timeChecksum(rv.Convert(typeTime).Interface().(time.Time)), true
+	}
+
+	if plan.binaryMarshaler != implNone {
+		if data, err := implementer(plan.binaryMarshaler, prv, rv).Interface().(encoding.BinaryMarshaler).MarshalBinary(); err == nil {
+			return ctx.fold(ctx.nameChecksum(markerStruct), ctx.nameChecksum(plan.typeName), ctx.hf(data)), true
+		}
+	}
+	if plan.textMarshaler != implNone {
+		if data, err := implementer(plan.textMarshaler, prv, rv).Interface().(encoding.TextMarshaler).MarshalText(); err == nil {
+			return ctx.fold(ctx.nameChecksum(markerStruct), ctx.nameChecksum(plan.typeName), ctx.hf(data)), true
+		}
+	}
+	return nil, false
+}
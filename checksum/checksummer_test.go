@@ -0,0 +1,219 @@
+package checksum
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+type MyChecksummer struct {
+	ID    string
+	Cache map[string]interface{}
+}
+
+func (c MyChecksummer) Checksum(hf HashFunc) []byte {
+	return hf([]byte(c.ID))
+}
+
+type MyChecksummerPtr struct {
+	ID    string
+	Cache map[string]interface{}
+}
+
+func (c *MyChecksummerPtr) Checksum(hf HashFunc) []byte {
+	return hf([]byte(c.ID))
+}
+
+var _ Checksummer = MyChecksummer{}
+var _ Checksummer = (*MyChecksummerPtr)(nil)
+
+func TestChecksum_Checksummer(t *testing.T) {
+	testName := "TestChecksum_Checksummer"
+	v1 := MyChecksummer{ID: "1", Cache: map[string]interface{}{"a": 1}}
+	v2 := &MyChecksummer{ID: "1", Cache: map[string]interface{}{"b": 2}}
+	v3 := MyChecksummer{ID: "2", Cache: map[string]interface{}{"a": 1}}
+	v4 := MyChecksummerPtr{ID: "1", Cache: map[string]interface{}{"a": 1}}
+	v5 := &MyChecksummerPtr{ID: "1"}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, v2))
+			checksum3 := fmt.Sprintf("%x", Checksum(hf, v3))
+			checksum4 := fmt.Sprintf("%x", Checksum(hf, v4))
+			checksum5 := fmt.Sprintf("%x", Checksum(hf, v5))
+			if checksum1 != checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+			if checksum1 == checksum3 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v3, checksum3)
+			}
+			if checksum4 != checksum5 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v4, checksum4, v5, checksum5)
+			}
+			if checksum1 == checksum4 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v4, checksum4)
+			}
+
+			// Checksummer nested in a slice and a map
+			checksum1 = fmt.Sprintf("%x", Checksum(hf, []interface{}{v1, map[string]interface{}{"v": v4}}))
+			checksum2 = fmt.Sprintf("%x", Checksum(hf, []interface{}{v2, map[string]interface{}{"v": v5}}))
+			if checksum1 != checksum2 {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, checksum1, checksum2)
+			}
+		})
+	}
+}
+
+func TestChecksum_TextMarshaler(t *testing.T) {
+	testName := "TestChecksum_TextMarshaler"
+	ip1 := net.ParseIP("10.0.0.1")
+	ip2 := net.IPv4(10, 0, 0, 1).To4()
+	ip3 := net.ParseIP("10.0.0.2")
+	n1 := big.NewInt(1234567890)
+	n2, _ := new(big.Int).SetString("1234567890", 10)
+	n3 := big.NewInt(-1234567890)
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			if checksum1, checksum2 := fmt.Sprintf("%x", Checksum(hf, ip1)), fmt.Sprintf("%x", Checksum(hf, ip2)); checksum1 != checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, ip1, checksum1, ip2, checksum2)
+			}
+			if checksum1, checksum3 := fmt.Sprintf("%x", Checksum(hf, ip1)), fmt.Sprintf("%x", Checksum(hf, ip3)); checksum1 == checksum3 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, ip1, checksum1, ip3, checksum3)
+			}
+			if checksum1, checksum2 := fmt.Sprintf("%x", Checksum(hf, n1)), fmt.Sprintf("%x", Checksum(hf, *n2)); checksum1 != checksum2 {
+				t.Fatalf("%s failed: Checksum(%s)=%s must be the same as Checksum(%s)=%s", testName+"/"+name, n1, checksum1, n2, checksum2)
+			}
+			if checksum1, checksum3 := fmt.Sprintf("%x", Checksum(hf, n1)), fmt.Sprintf("%x", Checksum(hf, n3)); checksum1 == checksum3 {
+				t.Fatalf("%s failed: Checksum(%s)=%s must NOT be the same as Checksum(%s)=%s", testName+"/"+name, n1, checksum1, n3, checksum3)
+			}
+		})
+	}
+}
+
+type MyMarshaler struct {
+	Value  string
+	binary bool
+	fail   bool
+}
+
+func (m MyMarshaler) MarshalBinary() ([]byte, error) {
+	if m.fail {
+		return nil, errors.New("failed")
+	}
+	if m.binary {
+		return []byte(strings.ToUpper(m.Value)), nil
+	}
+	return []byte(m.Value), nil
+}
+
+func (m MyMarshaler) MarshalText() ([]byte, error) {
+	return []byte(strings.ToLower(m.Value)), nil
+}
+
+func TestChecksum_BinaryMarshaler(t *testing.T) {
+	testName := "TestChecksum_BinaryMarshaler"
+	v1 := MyMarshaler{Value: "Value"}
+	v2 := MyMarshaler{Value: "VALUE", binary: true}
+	v3 := MyMarshaler{Value: "value", binary: true}
+	v4 := MyMarshaler{Value: "value", fail: true}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, v2))
+			checksum3 := fmt.Sprintf("%x", Checksum(hf, v3))
+			checksum4 := fmt.Sprintf("%x", Checksum(hf, v4))
+			// BinaryMarshaler takes precedence over TextMarshaler
+			if checksum1 == checksum2 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+			if checksum2 != checksum3 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v2, checksum2, v3, checksum3)
+			}
+			// if MarshalBinary fails, MarshalText is used
+			if checksum4 == checksum3 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, v4, checksum4, v3, checksum3)
+			}
+		})
+	}
+}
+
+type MyEmbeddedTime struct {
+	time.Time
+	Name string
+}
+
+type MyEmbeddedChecksummer struct {
+	MyChecksummer
+	Name string
+}
+
+type MyEmbeddedTimeMarshaler struct {
+	time.Time
+	Name string
+}
+
+func (m MyEmbeddedTimeMarshaler) MarshalBinary() ([]byte, error) {
+	return []byte(m.Name), nil
+}
+
+type MyEmbeddedPtr struct {
+	*big.Int
+	Name string
+}
+
+type MyEmbeddedChecksummerPtr struct {
+	MyChecksummerPtr
+	Name string
+}
+
+type MyTimeMarshaler struct {
+	At   time.Time
+	Name string
+}
+
+func (m MyTimeMarshaler) MarshalBinary() ([]byte, error) {
+	return []byte(m.Name), nil
+}
+
+func TestChecksum_PromotedMethods(t *testing.T) {
+	testName := "TestChecksum_PromotedMethods"
+	now := time.Now()
+	testData := []struct {
+		v1, v2 interface{}
+		same   bool
+	}{
+		// methods promoted from embedded fields are ignored, all fields are included
+		{MyEmbeddedTime{now, "a"}, MyEmbeddedTime{now, "b"}, false},
+		{&MyEmbeddedTime{now, "a"}, &MyEmbeddedTime{now, "b"}, false},
+		{MyEmbeddedTime{now, "a"}, MyEmbeddedTime{now.Add(time.Second), "a"}, false},
+		{MyEmbeddedChecksummer{MyChecksummer{ID: "1"}, "a"}, MyEmbeddedChecksummer{MyChecksummer{ID: "1"}, "b"}, false},
+		// methods promoted through embedded pointers, or from pointer methods of embedded values
+		{MyEmbeddedPtr{big.NewInt(1), "a"}, MyEmbeddedPtr{big.NewInt(1), "b"}, false},
+		{&MyEmbeddedPtr{big.NewInt(1), "a"}, &MyEmbeddedPtr{big.NewInt(1), "b"}, false},
+		{&MyEmbeddedChecksummerPtr{MyChecksummerPtr{ID: "1"}, "a"}, &MyEmbeddedChecksummerPtr{MyChecksummerPtr{ID: "1"}, "b"}, false},
+		// re-declaring the method does not help, as it cannot be told from a promoted one
+		{MyEmbeddedTimeMarshaler{now, "a"}, MyEmbeddedTimeMarshaler{now.Add(time.Second), "a"}, false},
+		// methods declared on types without embedded fields are honoured
+		{MyTimeMarshaler{now, "a"}, MyTimeMarshaler{now.Add(time.Second), "a"}, true},
+		{MyTimeMarshaler{now, "a"}, MyTimeMarshaler{now, "b"}, false},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, td := range testData {
+				checksum1 := fmt.Sprintf("%x", Checksum(hf, td.v1))
+				checksum2 := fmt.Sprintf("%x", Checksum(hf, td.v2))
+				if (checksum1 == checksum2) != td.same {
+					t.Fatalf("%s failed: Checksum(%#v)=%s / Checksum(%#v)=%s, expected same: %#v", testName+"/"+name, td.v1, checksum1, td.v2, checksum2, td.same)
+				}
+			}
+		})
+	}
+}
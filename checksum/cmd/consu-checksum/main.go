@@ -0,0 +1,168 @@
+/*
+Command consu-checksum calculates semantic checksums of JSON documents: key order, whitespace and number formatting
+(e.g. 1 vs 1.0) do not affect the checksums.
+
+Usage:
+
+	consu-checksum [-alg sha256] [-enc hex] [file ...]
+	consu-checksum -diff file1 file2
+
+Each file is decoded into a generic tree (maps, slices, strings, numbers, booleans and nulls), whose checksum is
+calculated by checksum.ChecksumWithOptions with numbers normalized. If no file is given, or a file is "-", the document
+is read from the standard input; "-" can be given at most once. A line "<checksum>  <file>" is printed for each file.
+YAML documents are not supported, as the module has no third-party dependencies: convert them to JSON first.
+
+With -diff, the paths (e.g. "services[1].port") whose values differ between the two files are printed, one per line;
+"." denotes the whole document. The exit code is 0 if the documents are the same, 1 if they differ, 2 on errors.
+
+Flags:
+
+	-alg   hash algorithm: crc32, crc64, fnv1a64, fnv1a128, xxh64, md5, sha1, sha256 (default), sha512, sha512-256 (or sha3-256 with Go 1.24+)
+	-enc   output encoding: hex (default), base64url, base32 or digest (the self-describing form "<algorithm>:<hex>")
+	-diff  list paths whose values differ between two files
+*/
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/btnguyen2k/consu/checksum"
+)
+
+// opts is the checksum options used for JSON documents: numbers hash by value, regardless of their formatting.
+var opts = checksum.Options{NormalizeNumbers: true}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with the given arguments and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("consu-checksum", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	alg := flags.String("alg", checksum.AlgSha256, "hash algorithm")
+	enc := flags.String("enc", "hex", "output encoding: hex, base64url, base32 or digest")
+	diff := flags.Bool("diff", false, "list paths whose values differ between two files")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	hf, ok := checksum.LookupAlgorithm(*alg)
+	if !ok {
+		fmt.Fprintf(stderr, "unknown algorithm [%s]\n", *alg)
+		return 2
+	}
+	files := flags.Args()
+	if countStdin(files) > 1 {
+		fmt.Fprintln(stderr, `the standard input ("-") can be read only once`)
+		return 2
+	}
+
+	if *diff {
+		if len(files) != 2 {
+			fmt.Fprintln(stderr, "-diff requires exactly two files")
+			return 2
+		}
+		return runDiff(hf, files, stdin, stdout, stderr)
+	}
+
+	encode, err := encoder(*enc)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	exitCode := 0
+	for _, file := range files {
+		v, err := readJSON(file, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", file, err)
+			exitCode = 2
+			continue
+		}
+		d := checksum.Digest{Algorithm: *alg, Sum: checksum.ChecksumWithOptions(hf, v, opts)}
+		fmt.Fprintf(stdout, "%s  %s\n", encode(d), file)
+	}
+	return exitCode
+}
+
+func runDiff(hf checksum.HashFunc, files []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var trees [2]*checksum.TreeNode
+	for i, file := range files {
+		v, err := readJSON(file, stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", file, err)
+			return 2
+		}
+		trees[i] = checksum.TreeWithOptions(hf, v, opts)
+	}
+	paths := checksum.Diff(trees[0], trees[1])
+	for _, path := range paths {
+		if path == "" {
+			path = "."
+		}
+		fmt.Fprintln(stdout, path)
+	}
+	if len(paths) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// encoder returns the function that encodes digests in the named output encoding.
+func encoder(name string) (func(checksum.Digest) string, error) {
+	switch name {
+	case "hex":
+		return checksum.Digest.Hex, nil
+	case "base64url":
+		return checksum.Digest.Base64URL, nil
+	case "base32":
+		return checksum.Digest.Base32, nil
+	case "digest":
+		return checksum.Digest.String, nil
+	}
+	return nil, fmt.Errorf("unknown encoding [%s]", name)
+}
+
+// countStdin returns the number of files that are the standard input ("-").
+func countStdin(files []string) int {
+	n := 0
+	for _, file := range files {
+		if file == "-" {
+			n++
+		}
+	}
+	return n
+}
+
+// readJSON reads a JSON document from a file ("-" for the standard input) and decodes it into a generic tree.
+func readJSON(file string, stdin io.Reader) (interface{}, error) {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = ioutil.ReadAll(stdin)
+	} else {
+		data, err = ioutil.ReadFile(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the JSON document")
+	}
+	return v, nil
+}
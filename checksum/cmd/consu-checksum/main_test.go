@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/btnguyen2k/consu/checksum"
+)
+
+func writeTestFiles(t *testing.T, contents map[string]string) string {
+	dir, err := ioutil.TempDir("", "consu-checksum")
+	if err != nil {
+		t.Fatalf("%s failed: %s", t.Name(), err)
+	}
+	for name, content := range contents {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("%s failed: %s", t.Name(), err)
+		}
+	}
+	return dir
+}
+
+func runTest(args []string, stdin string) (int, string, string) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	exitCode := run(args, strings.NewReader(stdin), stdout, stderr)
+	return exitCode, stdout.String(), stderr.String()
+}
+
+func TestRun_Checksum(t *testing.T) {
+	testName := "TestRun_Checksum"
+	dir := writeTestFiles(t, map[string]string{
+		"a.json": `{"name": "svc", "port": 8080, "tags": ["x", "y"], "ratio": 0.5}`,
+		"b.json": "{\n  \"ratio\": 5e-1,\n  \"tags\": [\"x\", \"y\"],\n  \"port\": 8080.0,\n  \"name\": \"svc\"\n}\n",
+		"c.json": `{"name": "svc", "port": 8081, "tags": ["x", "y"], "ratio": 0.5}`,
+	})
+	defer os.RemoveAll(dir)
+	a, b, c := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json"), filepath.Join(dir, "c.json")
+
+	exitCode, stdout, stderr := runTest([]string{a, b, c}, "")
+	if exitCode != 0 || stderr != "" {
+		t.Fatalf("%s failed: exit code %d, stderr %s", testName, exitCode, stderr)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("%s failed: expected 3 lines but received %#v", testName, lines)
+	}
+	sums := make([]string, len(lines))
+	for i, line := range lines {
+		sums[i] = strings.Fields(line)[0]
+	}
+	if sums[0] != sums[1] || sums[0] == sums[2] || len(sums[0]) != 64 {
+		t.Fatalf("%s failed: unexpected checksums %#v", testName, sums)
+	}
+
+	// stdin
+	_, stdout, _ = runTest(nil, `{"tags":["x","y"],"name":"svc","ratio":0.5,"port":8080}`)
+	if expected := sums[0] + "  -\n"; stdout != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, stdout)
+	}
+}
+
+func TestRun_AlgAndEnc(t *testing.T) {
+	testName := "TestRun_AlgAndEnc"
+	md5Digest, _ := checksum.DigestOf(checksum.AlgMd5, "a string")
+	crc32Digest, _ := checksum.DigestOf(checksum.AlgCrc32, "a string")
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{args: []string{"-alg", "md5"}, expected: md5Digest.Hex()},
+		{args: []string{"-alg", "md5", "-enc", "digest"}, expected: md5Digest.String()},
+		{args: []string{"-alg", "crc32", "-enc", "base64url"}, expected: crc32Digest.Base64URL()},
+		{args: []string{"-alg", "crc32", "-enc", "base32"}, expected: crc32Digest.Base32()},
+	}
+	for _, testCase := range testCases {
+		t.Run(strings.Join(testCase.args, " "), func(t *testing.T) {
+			exitCode, stdout, stderr := runTest(testCase.args, `"a string"`)
+			if expected := testCase.expected + "  -\n"; exitCode != 0 || stderr != "" || stdout != expected {
+				t.Fatalf("%s failed: expected %#v but received exit code %d, stdout %#v, stderr %#v", testName, expected, exitCode, stdout, stderr)
+			}
+		})
+	}
+}
+
+func TestRun_Diff(t *testing.T) {
+	testName := "TestRun_Diff"
+	dir := writeTestFiles(t, map[string]string{
+		"a.json": `{"name": "svc", "services": [{"port": 80}, {"port": 443, "tls": true}], "debug": false}`,
+		"b.json": `{"debug": false, "name": "svc", "services": [{"port": 80.0}, {"port": 8443, "tls": true}], "extra": 1}`,
+		"c.json": `{"debug": false, "services": [{"port": 80}, {"tls": true, "port": 443}], "name": "svc"}`,
+		"d.json": `"a string"`,
+	})
+	defer os.RemoveAll(dir)
+	a, b, c := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json"), filepath.Join(dir, "c.json")
+
+	exitCode, stdout, _ := runTest([]string{"-diff", a, b}, "")
+	if expected := "services[1].port\nextra\n"; exitCode != 1 || stdout != expected {
+		t.Fatalf("%s failed: expected exit code 1 and %#v but received %d and %#v", testName, expected, exitCode, stdout)
+	}
+	if exitCode, stdout, _ = runTest([]string{"--diff", a, c}, ""); exitCode != 0 || stdout != "" {
+		t.Fatalf("%s failed: expected exit code 0 and no output but received %d and %#v", testName, exitCode, stdout)
+	}
+	if exitCode, stdout, _ = runTest([]string{"-diff", filepath.Join(dir, "d.json"), "-"}, `"another string"`); exitCode != 1 || stdout != ".\n" {
+		t.Fatalf("%s failed: expected exit code 1 and %#v but received %d and %#v", testName, ".\n", exitCode, stdout)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	testName := "TestRun_Errors"
+	testCases := []struct {
+		name  string
+		args  []string
+		stdin string
+	}{
+		{name: "unknown_flag", args: []string{"-unknown"}},
+		{name: "unknown_alg", args: []string{"-alg", "unknown"}},
+		{name: "unknown_enc", args: []string{"-enc", "unknown"}},
+		{name: "invalid_json", stdin: `{"a":`},
+		{name: "trailing_data", stdin: `{"a":1} {"b":2}`},
+		{name: "file_not_found", args: []string{"not-exist.json"}},
+		{name: "diff_one_file", args: []string{"-diff", "-"}},
+		{name: "diff_invalid_json", args: []string{"-diff", "-", "not-exist.json"}, stdin: `[`},
+		{name: "diff_stdin_twice", args: []string{"-diff", "-", "-"}, stdin: `{"a":1}`},
+		{name: "stdin_twice", args: []string{"-", "-"}, stdin: `{"a":1}`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			exitCode, stdout, stderr := runTest(testCase.args, testCase.stdin)
+			if exitCode != 2 || stderr == "" {
+				t.Fatalf("%s failed: expected exit code 2 and an error message but received %d and %#v", testName+"/"+testCase.name, exitCode, stderr)
+			}
+			if strings.HasSuffix(testCase.name, "stdin_twice") && (stdout != "" || !strings.Contains(stderr, "only once")) {
+				t.Fatalf("%s failed: expected a usage error before reading the input but received %#v / %#v", testName+"/"+testCase.name, stdout, stderr)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package checksum
+
+import (
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Identifiers of the built-in algorithms, used by Digest.
+//
+// @Available since <<VERSION>>
+const (
+	AlgCrc32      = "crc32"
+	AlgCrc64      = "crc64"
+	AlgFnv1a64    = "fnv1a64"
+	AlgFnv1a128   = "fnv1a128"
+	AlgXxHash64   = "xxh64"
+	AlgMd5        = "md5"
+	AlgSha1       = "sha1"
+	AlgSha256     = "sha256"
+	AlgSha512     = "sha512"
+	AlgSha512_256 = "sha512-256"
+	AlgSha3_256   = "sha3-256" // available with Go 1.24+
+)
+
+var (
+	algorithmsLock sync.RWMutex
+	algorithms     = map[string]HashFunc{
+		AlgCrc32:      Crc32HashFunc,
+		AlgCrc64:      Crc64HashFunc,
+		AlgFnv1a64:    Fnv1a64HashFunc,
+		AlgFnv1a128:   Fnv1a128HashFunc,
+		AlgXxHash64:   XxHash64HashFunc,
+		AlgMd5:        Md5HashFunc,
+		AlgSha1:       Sha1HashFunc,
+		AlgSha256:     Sha256HashFunc,
+		AlgSha512:     Sha512HashFunc,
+		AlgSha512_256: Sha512_256HashFunc,
+	}
+)
+
+// RegisterAlgorithm registers a hash function under an algorithm identifier, so that digests of the algorithm can be
+// calculated, parsed and verified, e.g. RegisterAlgorithm("hmac-sha256", HmacHashFunc(sha256.New, key)).
+// Registering an existing identifier replaces its hash function.
+//
+// The identifier must be non-empty and must not contain ':'.
+//
+// @Available since <<VERSION>>
+func RegisterAlgorithm(alg string, hf HashFunc) error {
+	if alg == "" || strings.Contains(alg, ":") {
+		return fmt.Errorf("invalid algorithm identifier [%s]", alg)
+	}
+	if hf == nil {
+		return fmt.Errorf("nil hash function for algorithm [%s]", alg)
+	}
+	algorithmsLock.Lock()
+	defer algorithmsLock.Unlock()
+	algorithms[alg] = hf
+	return nil
+}
+
+// LookupAlgorithm returns the hash function registered under an algorithm identifier.
+//
+// @Available since <<VERSION>>
+func LookupAlgorithm(alg string) (HashFunc, bool) {
+	algorithmsLock.RLock()
+	defer algorithmsLock.RUnlock()
+	hf, ok := algorithms[alg]
+	return hf, ok
+}
+
+// UnknownAlgorithmError is returned when a digest refers to an algorithm that has not been registered.
+//
+// @Available since <<VERSION>>
+type UnknownAlgorithmError struct {
+	Algorithm string
+}
+
+// Error implements interface error.
+func (e *UnknownAlgorithmError) Error() string {
+	return fmt.Sprintf("unknown algorithm [%s]", e.Algorithm)
+}
+
+func lookupAlgorithm(alg string) (HashFunc, error) {
+	hf, ok := LookupAlgorithm(alg)
+	if !ok {
+		return nil, &UnknownAlgorithmError{Algorithm: alg}
+	}
+	return hf, nil
+}
+
+// Digest is a checksum together with the identifier of the algorithm used to calculate it, so that stored digests are
+// self-describing, e.g. "sha256:6d1d8e...". See RegisterAlgorithm for the list of algorithms.
+//
+// @Available since <<VERSION>>
+type Digest struct {
+	Algorithm string
+	Sum       []byte
+}
+
+// DigestOf calculates checksum of an input using the algorithm registered under alg.
+//
+// @Available since <<VERSION>>
+func DigestOf(alg string, v interface{}) (Digest, error) {
+	hf, err := lookupAlgorithm(alg)
+	if err != nil {
+		return Digest{}, err
+	}
+	return Digest{Algorithm: alg, Sum: Checksum(hf, v)}, nil
+}
+
+// Parse parses a digest in the prefixed form "<algorithm>:<hex-encoded sum>", e.g. "md5:d41d8cd98f00b204e9800998ecf8427e".
+//
+// The algorithm must be registered, and the length of the sum must match the output size of the algorithm.
+//
+// @Available since <<VERSION>>
+func Parse(s string) (Digest, error) {
+	i := strings.Index(s, ":")
+	if i < 0 {
+		return Digest{}, fmt.Errorf("invalid digest [%s]: expected form <algorithm>:<hex>", s)
+	}
+	alg := s[:i]
+	hf, err := lookupAlgorithm(alg)
+	if err != nil {
+		return Digest{}, err
+	}
+	sum, err := hex.DecodeString(s[i+1:])
+	if err != nil {
+		return Digest{}, fmt.Errorf("invalid digest [%s]: %s", s, err)
+	}
+	if size := len(hf(nil)); len(sum) != size {
+		return Digest{}, fmt.Errorf("invalid digest [%s]: expected %d bytes for algorithm [%s] but received %d", s, size, alg, len(sum))
+	}
+	return Digest{Algorithm: alg, Sum: sum}, nil
+}
+
+// Verify checks if v matches the digest, using the hash function registered under the digest's algorithm.
+// An UnknownAlgorithmError is returned if the algorithm has not been registered.
+//
+// @Available since <<VERSION>>
+func Verify(v interface{}, d Digest) (bool, error) {
+	hf, err := lookupAlgorithm(d.Algorithm)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(Checksum(hf, v), d.Sum) == 1, nil
+}
+
+// Hex returns the lower-case hex-encoded sum.
+func (d Digest) Hex() string {
+	return hex.EncodeToString(d.Sum)
+}
+
+// Base64URL returns the sum encoded with the URL-safe base64 alphabet, without padding.
+func (d Digest) Base64URL() string {
+	return base64.RawURLEncoding.EncodeToString(d.Sum)
+}
+
+// Base32 returns the sum encoded with the standard base32 alphabet, without padding.
+func (d Digest) Base32() string {
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(d.Sum)
+}
+
+// String returns the prefixed form of the digest, "<algorithm>:<hex-encoded sum>", which can be parsed by Parse.
+func (d Digest) String() string {
+	return d.Algorithm + ":" + d.Hex()
+}
+
+// Equal checks if two digests have the same algorithm and sum.
+func (d Digest) Equal(other Digest) bool {
+	return d.Algorithm == other.Algorithm && subtle.ConstantTimeCompare(d.Sum, other.Sum) == 1
+}
+
+// MarshalText implements encoding.TextMarshaler, so that digests are stored in their prefixed forms, e.g. in JSON.
+func (d Digest) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Digest) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
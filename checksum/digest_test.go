@@ -0,0 +1,141 @@
+package checksum
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var algList = []string{AlgCrc32, AlgMd5, AlgSha1, AlgSha256, AlgSha512, AlgSha512_256, AlgCrc64, AlgFnv1a64, AlgFnv1a128, AlgXxHash64}
+
+func TestDigest_Encodings(t *testing.T) {
+	testName := "TestDigest_Encodings"
+	d := Digest{Algorithm: AlgSha256, Sum: []byte{0xfb, 0xff, 0x00, 0x10, 0x20}}
+	testCases := []struct {
+		name     string
+		encoded  string
+		expected string
+	}{
+		{name: "hex", encoded: d.Hex(), expected: "fbff001020"},
+		{name: "base64url", encoded: d.Base64URL(), expected: "-_8AECA"},
+		{name: "base32", encoded: d.Base32(), expected: "7P7QAEBA"},
+		{name: "string", encoded: d.String(), expected: "sha256:fbff001020"},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			if testCase.encoded != testCase.expected {
+				t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+testCase.name, testCase.expected, testCase.encoded)
+			}
+		})
+	}
+}
+
+func TestDigestOf(t *testing.T) {
+	testName := "TestDigestOf"
+	v := map[string]interface{}{"a": 1, "b": []string{"x", "y"}}
+	for i, alg := range algList {
+		t.Run(alg, func(t *testing.T) {
+			d, err := DigestOf(alg, v)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+alg, err)
+			}
+			if expected := Checksum(hfList[i], v); d.Algorithm != alg || !reflect.DeepEqual(d.Sum, expected) {
+				t.Fatalf("%s failed: expected %s:%x but received %s", testName+"/"+alg, alg, expected, d)
+			}
+		})
+	}
+	var errUnknown *UnknownAlgorithmError
+	if _, err := DigestOf("unknown", v); !errors.As(err, &errUnknown) || errUnknown.Algorithm != "unknown" {
+		t.Fatalf("%s failed: expected UnknownAlgorithmError but received %#v", testName, err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	testName := "TestParse"
+	for _, alg := range algList {
+		t.Run(alg, func(t *testing.T) {
+			d, _ := DigestOf(alg, "a string")
+			parsed, err := Parse(d.String())
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+alg, err)
+			}
+			if !parsed.Equal(d) {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+alg, d, parsed)
+			}
+		})
+	}
+	for _, s := range []string{"", "d41d8cd98f00b204e9800998ecf8427e", "unknown:00", "md5:xyz", "md5:d41d8cd98f00b204", "sha256:d41d8cd98f00b204e9800998ecf8427e"} {
+		if _, err := Parse(s); err == nil {
+			t.Fatalf("%s failed: expected error parsing %#v", testName, s)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	testName := "TestVerify"
+	v1 := MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4}
+	v2 := MyStructPubPriv{S: "string", N: 1, F: 2.3}
+	for _, alg := range algList {
+		t.Run(alg, func(t *testing.T) {
+			d, _ := DigestOf(alg, v1)
+			parsed, _ := Parse(d.String())
+			if ok, err := Verify(&v1, parsed); err != nil || !ok {
+				t.Fatalf("%s failed: %#v must match digest %s (error: %v)", testName+"/"+alg, v1, d, err)
+			}
+			if ok, err := Verify(v2, parsed); err != nil || ok {
+				t.Fatalf("%s failed: %#v must NOT match digest %s (error: %v)", testName+"/"+alg, v2, d, err)
+			}
+		})
+	}
+	if _, err := Verify(v1, Digest{Algorithm: "unknown"}); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+}
+
+func TestRegisterAlgorithm(t *testing.T) {
+	testName := "TestRegisterAlgorithm"
+	alg := "hmac-sha256-test"
+	if err := RegisterAlgorithm(alg, HmacHashFunc(sha256.New, []byte("secret"))); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	d, err := DigestOf(alg, "a string")
+	if err != nil || !strings.HasPrefix(d.String(), alg+":") {
+		t.Fatalf("%s failed: unexpected digest %s (error: %v)", testName, d, err)
+	}
+	if ok, err := Verify("a string", d); err != nil || !ok {
+		t.Fatalf("%s failed: digest %s must be verified (error: %v)", testName, d, err)
+	}
+	for _, invalid := range []string{"", "a:b"} {
+		if err := RegisterAlgorithm(invalid, Sha256HashFunc); err == nil {
+			t.Fatalf("%s failed: expected error registering %#v", testName, invalid)
+		}
+	}
+	if err := RegisterAlgorithm("nil-hash", nil); err == nil {
+		t.Fatalf("%s failed: expected error registering nil hash function", testName)
+	}
+}
+
+func TestDigest_JSON(t *testing.T) {
+	testName := "TestDigest_JSON"
+	d, _ := DigestOf(AlgSha256, "a string")
+	type record struct {
+		Digest Digest `json:"digest"`
+	}
+	js, err := json.Marshal(record{Digest: d})
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if expected := `{"digest":"` + d.String() + `"}`; string(js) != expected {
+		t.Fatalf("%s failed: expected %s but received %s", testName, expected, js)
+	}
+	var decoded record
+	if err := json.Unmarshal(js, &decoded); err != nil || !decoded.Digest.Equal(d) {
+		t.Fatalf("%s failed: expected %s but received %s (error: %v)", testName, d, decoded.Digest, err)
+	}
+	if err := json.Unmarshal([]byte(`{"digest":"invalid"}`), &decoded); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+}
@@ -0,0 +1,120 @@
+package checksum
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// FSOptions controls how checksum of a directory tree is calculated, see FSWithOptions.
+//
+// @Available since <<VERSION>>
+type FSOptions struct {
+	// IncludeMode, if true, includes the file mode (type and permission bits) of files in the checksum.
+	IncludeMode bool
+
+	// IncludeModTime, if true, includes the modification time of files in the checksum.
+	IncludeModTime bool
+
+	// Exclude is a list of glob patterns (see path.Match), matched against both the slash-separated path of a file or
+	// directory relative to the root and its base name, e.g. "*.tmp" or "build/*". Matched files are excluded from
+	// the checksum; so are matched directories and everything in them. FSWithOptions returns an error wrapping
+	// path.ErrBadPattern if a pattern is malformed.
+	Exclude []string
+}
+
+// FS calculates checksum of the directory tree rooted at root in fsys, using default FSOptions.
+//
+// Note: files are loaded into memory one at a time, see FSWithOptions.
+//
+// @Available since <<VERSION>>
+func FS(hf HashFunc, fsys fs.FS, root string) ([]byte, error) {
+	return FSWithOptions(hf, fsys, root, FSOptions{})
+}
+
+/*
+FSWithOptions calculates checksum of the directory tree rooted at root in fsys.
+
+The directory tree is hashed the same way a map is: order-independent, keyed by the slash-separated paths of files
+relative to root. Only regular files are included, empty directories do not contribute to the checksum.
+With default options, the checksum is the same as checksum of map[string]string{relativePath: fileContent}.
+
+Files are read one at a time: each file is loaded into memory while its checksum is calculated, so memory usage is
+bounded by the size of the largest file (plus one checksum per file).
+
+If IncludeMode or IncludeModTime is true, the value of each file is a map[string]interface{} instead of its content:
+"content" is the file content (as a string), "mode" is the file mode (as an uint32) and "modtime" is the modification
+time of the file (as a time.Time), e.g. map[string]interface{}{"content": fileContent, "mode": uint32(fileMode)}.
+
+@Available since <<VERSION>>
+*/
+func FSWithOptions(hf HashFunc, fsys fs.FS, root string, opts FSOptions) ([]byte, error) {
+	for _, pattern := range opts.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	ctx := newChecksumContext(hf, Options{})
+	var checksums [][]byte
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relPath := path.Base(p)
+		if p != root {
+			relPath = strings.TrimPrefix(p, root+"/")
+			if root == "." {
+				relPath = p
+			}
+			if isExcluded(opts.Exclude, relPath) {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		var v interface{} = string(data)
+		if opts.IncludeMode || opts.IncludeModTime {
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			m := map[string]interface{}{"content": v}
+			if opts.IncludeMode {
+				m["mode"] = uint32(fi.Mode())
+			}
+			if opts.IncludeModTime {
+				m["modtime"] = fi.ModTime()
+			}
+			v = m
+		}
+		checksums = append(checksums, ctx.fold(checksumSafe(ctx, relPath), checksumSafe(ctx, v)))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ctx.foldSorted([]string{markerMap}, checksums), nil
+}
+
+// isExcluded checks if a path, or its base name, matches any of the glob patterns, which have been validated.
+func isExcluded(patterns []string, p string) bool {
+	base := path.Base(p)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, p); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, base); matched {
+			return true
+		}
+	}
+	return false
+}
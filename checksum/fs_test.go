@@ -0,0 +1,112 @@
+package checksum
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func newTestFS() fstest.MapFS {
+	modTime := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	return fstest.MapFS{
+		"README.md":         {Data: []byte("readme"), Mode: 0644, ModTime: modTime},
+		"src/main.go":       {Data: []byte("package main"), Mode: 0644, ModTime: modTime},
+		"src/util/util.go":  {Data: []byte("package util"), Mode: 0644, ModTime: modTime},
+		"src/util/util.tmp": {Data: []byte("temp"), Mode: 0644, ModTime: modTime},
+		"build/app":         {Data: []byte("binary"), Mode: 0755, ModTime: modTime},
+		"build/cache/a.o":   {Data: []byte("object"), Mode: 0644, ModTime: modTime},
+		"empty/placeholder": {Mode: fs.ModeDir | 0755},
+	}
+}
+
+func TestFS(t *testing.T) {
+	testName := "TestFS"
+	fsys := newTestFS()
+	testCases := []struct {
+		name     string
+		root     string
+		expected map[string]string
+	}{
+		{name: "all", root: ".", expected: map[string]string{
+			"README.md": "readme", "src/main.go": "package main", "src/util/util.go": "package util", "src/util/util.tmp": "temp",
+			"build/app": "binary", "build/cache/a.o": "object",
+		}},
+		{name: "subdir", root: "src", expected: map[string]string{"main.go": "package main", "util/util.go": "package util", "util/util.tmp": "temp"}},
+		{name: "file", root: "src/main.go", expected: map[string]string{"main.go": "package main"}},
+		{name: "empty", root: "empty", expected: map[string]string{}},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, testCase := range testCases {
+				checksum, err := FS(hf, fsys, testCase.root)
+				if err != nil {
+					t.Fatalf("%s failed: %s", testName+"/"+name+"/"+testCase.name, err)
+				}
+				if expected := Checksum(hf, testCase.expected); !reflect.DeepEqual(checksum, expected) {
+					t.Fatalf("%s failed: expected %x but received %x", testName+"/"+name+"/"+testCase.name, expected, checksum)
+				}
+			}
+		})
+	}
+	if _, err := FS(Sha256HashFunc, fsys, "not-exist"); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+}
+
+func TestFSWithOptions_Exclude(t *testing.T) {
+	testName := "TestFSWithOptions_Exclude"
+	fsys := newTestFS()
+	opts := FSOptions{Exclude: []string{"*.tmp", "build"}}
+	expected := Checksum(Sha256HashFunc, map[string]string{"README.md": "readme", "src/main.go": "package main", "src/util/util.go": "package util"})
+	checksum, err := FSWithOptions(Sha256HashFunc, fsys, ".", opts)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if !reflect.DeepEqual(checksum, expected) {
+		t.Fatalf("%s failed: expected %x but received %x", testName, expected, checksum)
+	}
+}
+
+func TestFSWithOptions_BadPattern(t *testing.T) {
+	testName := "TestFSWithOptions_BadPattern"
+	for _, pattern := range []string{"[a-", "a[", "\\"} {
+		// the pattern is rejected even if no file would be matched against it
+		checksum, err := FSWithOptions(Sha256HashFunc, newTestFS(), ".", FSOptions{Exclude: []string{"*.tmp", pattern}})
+		if !errors.Is(err, path.ErrBadPattern) || checksum != nil {
+			t.Fatalf("%s failed: {pattern: %#v / expected error %s but received (%x, %v)}", testName, pattern, path.ErrBadPattern, checksum, err)
+		}
+	}
+}
+
+func TestFSWithOptions_ModeAndModTime(t *testing.T) {
+	testName := "TestFSWithOptions_ModeAndModTime"
+	fsys := fstest.MapFS{"a.sh": newTestFS()["build/app"]}
+	file := fsys["a.sh"]
+	expected := Checksum(Sha256HashFunc, map[string]interface{}{
+		"a.sh": map[string]interface{}{"content": "binary", "mode": uint32(file.Mode), "modtime": file.ModTime},
+	})
+	checksum, err := FSWithOptions(Sha256HashFunc, fsys, ".", FSOptions{IncludeMode: true, IncludeModTime: true})
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if !reflect.DeepEqual(checksum, expected) {
+		t.Fatalf("%s failed: expected %x but received %x", testName, expected, checksum)
+	}
+
+	plain, _ := FS(Sha256HashFunc, fsys, ".")
+	modeOnly, _ := FSWithOptions(Sha256HashFunc, fsys, ".", FSOptions{IncludeMode: true})
+	file.Mode = 0644
+	modeChanged, _ := FSWithOptions(Sha256HashFunc, fsys, ".", FSOptions{IncludeMode: true})
+	plainModeChanged, _ := FS(Sha256HashFunc, fsys, ".")
+	if reflect.DeepEqual(plain, modeOnly) || reflect.DeepEqual(modeOnly, modeChanged) {
+		t.Fatalf("%s failed: file mode is expected to affect checksum", testName)
+	}
+	if !reflect.DeepEqual(plain, plainModeChanged) {
+		t.Fatalf("%s failed: file mode is not expected to affect checksum by default", testName)
+	}
+}
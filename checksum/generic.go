@@ -0,0 +1,69 @@
+package checksum
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Of is the typed counterpart of Checksum: it calculates checksum of a value of any type using the provided hash function.
+//
+// Of(hf, v) == Checksum(hf, v). Of walks v from its own address, so that calculating checksum of a struct (or a pointer
+// to it) does not need to copy the struct to read its unexported fields.
+//
+// @Available since <<VERSION>>
+func Of[T any](hf HashFunc, v T) []byte {
+	return OfWithOptions(hf, v, Options{})
+}
+
+// OfWithOptions is the typed counterpart of ChecksumWithOptions.
+//
+// @Available since <<VERSION>>
+func OfWithOptions[T any](hf HashFunc, v T, opts Options) []byte {
+	return checksumRootValue(newChecksumContext(hf, opts), reflect.ValueOf(&v).Elem())
+}
+
+// Cache memoizes the checksum of a value: the checksum is recalculated only when the caller-provided version key changes.
+// It is meant for values that are immutable for a given version, e.g. a config object keyed by its revision number,
+// where the same value is checksummed over and over.
+//
+// A Cache holds the checksum of one version at a time, so one Cache should be used per value identity.
+// A Cache is safe for concurrent use.
+//
+// @Available since <<VERSION>>
+type Cache[K comparable] struct {
+	hf       HashFunc
+	opts     Options
+	lock     sync.Mutex
+	valid    bool
+	key      K
+	checksum []byte
+}
+
+// NewCache creates a new Cache that calculates checksums using the provided hash function and options.
+//
+// @Available since <<VERSION>>
+func NewCache[K comparable](hf HashFunc, opts Options) *Cache[K] {
+	return &Cache[K]{hf: hf, opts: opts}
+}
+
+// Checksum returns checksum of v, which is recalculated only if key differs from the key of the previous call
+// (or the cache has been reset). The caller is responsible for changing the key whenever v changes.
+//
+// The returned slice is a copy, so it can be modified by the caller.
+func (c *Cache[K]) Checksum(key K, v interface{}) []byte {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.valid || c.key != key {
+		c.checksum = ChecksumWithOptions(c.hf, v, c.opts)
+		c.key, c.valid = key, true
+	}
+	return append([]byte{}, c.checksum...)
+}
+
+// Reset clears the cache, so that the next call to Checksum recalculates the checksum.
+func (c *Cache[K]) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var zero K
+	c.key, c.valid, c.checksum = zero, false, nil
+}
@@ -0,0 +1,139 @@
+package checksum
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestOf(t *testing.T) {
+	testName := "TestOf"
+	now := time.Now()
+	myNow := MyTime(now)
+	node := &Node{Value: 1}
+	node.Next = node
+	v1 := MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checks := []struct {
+				v        interface{}
+				checksum []byte
+			}{
+				{nil, Of[interface{}](hf, nil)},
+				{(*int)(nil), Of(hf, (*int)(nil))},
+				{1, Of(hf, 1)},
+				{"a string", Of(hf, "a string")},
+				{now, Of(hf, now)},
+				{&myNow, Of(hf, &myNow)},
+				{[]int{1, 2, 3}, Of(hf, []int{1, 2, 3})},
+				{map[string]interface{}{"a": 1}, Of(hf, map[string]interface{}{"a": 1})},
+				{v1, Of(hf, v1)},
+				{&v1, Of(hf, &v1)},
+				{node, Of(hf, node)},
+				{MyChecksummerPtr{ID: "1"}, Of(hf, MyChecksummerPtr{ID: "1"})},
+				{MyStructCustom1{S: "string"}, Of(hf, MyStructCustom1{S: "string"})},
+			}
+			for _, check := range checks {
+				if expected := Checksum(hf, check.v); !reflect.DeepEqual(check.checksum, expected) {
+					t.Fatalf("%s failed: <%#v> expected %x but received %x", testName+"/"+name, check.v, expected, check.checksum)
+				}
+			}
+		})
+	}
+}
+
+func TestOfWithOptions(t *testing.T) {
+	testName := "TestOfWithOptions"
+	v := MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4}
+	for _, opts := range []Options{StrictOptions(), {IgnoreUnexported: true}, {NormalizeNumbers: true}} {
+		if expected, checksum := ChecksumWithOptions(Sha256HashFunc, v, opts), OfWithOptions(Sha256HashFunc, &v, opts); !reflect.DeepEqual(checksum, expected) {
+			t.Fatalf("%s failed: expected %x but received %x", testName, expected, checksum)
+		}
+	}
+}
+
+func TestCache(t *testing.T) {
+	testName := "TestCache"
+	cache := NewCache[int](Sha256HashFunc, DefaultOptions())
+	v := map[string]interface{}{"a": 1}
+	checksum1 := cache.Checksum(1, v)
+	if expected := Checksum(Sha256HashFunc, v); !reflect.DeepEqual(checksum1, expected) {
+		t.Fatalf("%s failed: expected %x but received %x", testName, expected, checksum1)
+	}
+
+	// same key: the cached checksum is returned, even if the value has changed
+	v["a"] = 2
+	if checksum := cache.Checksum(1, v); !reflect.DeepEqual(checksum, checksum1) {
+		t.Fatalf("%s failed: expected cached %x but received %x", testName, checksum1, checksum)
+	}
+
+	// returned checksums are copies
+	checksum1[0]++
+	if checksum := cache.Checksum(1, v); reflect.DeepEqual(checksum, checksum1) {
+		t.Fatalf("%s failed: cached checksum must not be modified by the caller", testName)
+	}
+
+	// new key: the checksum is recalculated
+	if expected, checksum := Checksum(Sha256HashFunc, v), cache.Checksum(2, v); !reflect.DeepEqual(checksum, expected) {
+		t.Fatalf("%s failed: expected %x but received %x", testName, expected, checksum)
+	}
+
+	// reset: the checksum is recalculated, even with the same key
+	v["a"] = 3
+	cache.Reset()
+	if expected, checksum := Checksum(Sha256HashFunc, v), cache.Checksum(2, v); !reflect.DeepEqual(checksum, expected) {
+		t.Fatalf("%s failed: expected %x but received %x", testName, expected, checksum)
+	}
+}
+
+func TestCache_ZeroKey(t *testing.T) {
+	testName := "TestCache_ZeroKey"
+	cache := NewCache[string](Md5HashFunc, StrictOptions())
+	if expected, checksum := ChecksumWithOptions(Md5HashFunc, 1, StrictOptions()), cache.Checksum("", 1); !reflect.DeepEqual(checksum, expected) {
+		t.Fatalf("%s failed: expected %x but received %x", testName, expected, checksum)
+	}
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	testName := "TestCache_Concurrent"
+	type version struct {
+		ID       string
+		Revision int
+	}
+	cache := NewCache[version](Sha256HashFunc, DefaultOptions())
+	values := []interface{}{"rev0", "rev1", "rev2"}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rev := (i + j) % len(values)
+				if expected, checksum := Checksum(Sha256HashFunc, values[rev]), cache.Checksum(version{"config", rev}, values[rev]); !reflect.DeepEqual(checksum, expected) {
+					t.Errorf("%s failed: expected %x but received %x", testName, expected, checksum)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func BenchmarkOf_Struct(b *testing.B) {
+	v := newBenchmarkStruct(1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Of(Sha256HashFunc, &v)
+	}
+}
+
+func BenchmarkCache_Struct(b *testing.B) {
+	v := newBenchmarkStruct(1)
+	cache := NewCache[int](Sha256HashFunc, DefaultOptions())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		cache.Checksum(1, &v)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/btnguyen2k/consu/checksum
 
-go 1.13
+go 1.18
@@ -0,0 +1,134 @@
+package checksum
+
+import (
+	"encoding"
+	"hash"
+)
+
+// Hasher calculates checksum of a sequence of values incrementally, without holding the whole sequence in memory.
+//
+// Values are added via WriteValue. Feeding a Hasher the elements of a slice one by one produces the same checksum
+// as calling Checksum on the whole slice, e.g. after
+//
+//	h := NewHasher(md5.New())
+//	for _, row := range rows {
+//		h.WriteValue(row)
+//	}
+//
+// h.Sum(nil) equals to Checksum(Md5HashFunc, rows).
+//
+// Hasher implements hash.Hash: bytes written via Write form a byte stream, which is added to the sequence as a single
+// string value (ended by the next WriteValue), so the checksum does not depend on how the stream is split into Write
+// calls, e.g. io.Copy(h, r) followed by h.Sum(nil) equals to Checksum(hf, []string{content of r}).
+//
+// Note: the checksum of an empty sequence is the hash of the empty-slice marker, so that Sum always returns Size() bytes;
+// it differs from Checksum of an empty slice. A Hasher is not safe for concurrent use.
+type Hasher struct {
+	h   hash.Hash
+	hf  HashFunc
+	buf []byte
+
+	empty     bool   // true if no value has been added
+	streaming bool   // true if bytes written via Write have not been added to the sequence yet
+	saveable  bool   // true if the state of h can be saved and restored, see Sum
+	pending   []byte // bytes written via Write, if h is not saveable
+}
+
+// NewHasher creates a new Hasher that uses the provided hash.Hash to calculate checksum.
+//
+// The Hasher takes ownership of h: h must not be used elsewhere while the Hasher is in use. If h implements
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler (as the hashes of the standard library do), bytes written via
+// Write are streamed into h; otherwise they are buffered until the next WriteValue.
+func NewHasher(h hash.Hash) *Hasher {
+	hasher := &Hasher{h: h}
+	hasher.hf = func(input []byte) []byte {
+		h.Reset()
+		return hashFunc(h, input)
+	}
+	_, marshaler := h.(encoding.BinaryMarshaler)
+	_, unmarshaler := h.(encoding.BinaryUnmarshaler)
+	hasher.saveable = marshaler && unmarshaler
+	hasher.Reset()
+	return hasher
+}
+
+// streamChecksum returns checksum of the bytes written via Write, i.e. checksum of the byte stream as a string.
+func (h *Hasher) streamChecksum() []byte {
+	if h.saveable {
+		return h.h.Sum(nil)
+	}
+	return h.hf(h.pending)
+}
+
+// flush adds the bytes written via Write to the sequence.
+func (h *Hasher) flush() {
+	if h.streaming {
+		h.buf = h.hf(append(h.buf, h.streamChecksum()...))
+		h.streaming, h.pending = false, nil
+	}
+}
+
+// WriteValue adds a value to the sequence.
+//
+// The value is processed the same way as an element of a slice passed to Checksum. Bytes written via Write before are
+// added to the sequence first, as a single string value.
+func (h *Hasher) WriteValue(v interface{}) {
+	h.flush()
+	h.buf = h.hf(append(h.buf, checksumSafe(newChecksumContext(h.hf, Options{}), v)...))
+	h.empty = false
+}
+
+// Write appends p to the byte stream, which is added to the sequence as a single string value. It never returns an error.
+//
+// Consecutive calls of Write are the same as one call with the concatenated input.
+func (h *Hasher) Write(p []byte) (int, error) {
+	if !h.streaming {
+		h.h.Reset()
+		h.streaming, h.empty = true, false
+	}
+	if h.saveable {
+		h.h.Write(p)
+	} else {
+		h.pending = append(h.pending, p...)
+	}
+	return len(p), nil
+}
+
+// Sum appends the current checksum to b and returns the resulting slice. It does not change the underlying state.
+func (h *Hasher) Sum(b []byte) []byte {
+	if h.empty {
+		return append(b, h.hf([]byte(markerSliceArray))...)
+	}
+	if !h.streaming {
+		return append(b, h.buf...)
+	}
+	if !h.saveable {
+		return append(b, h.hf(append(append([]byte{}, h.buf...), h.streamChecksum()...))...)
+	}
+	// the checksum is calculated with h, so the state of the byte stream is saved and restored afterwards
+	state, err := h.h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	checksum := h.hf(append(append([]byte{}, h.buf...), h.streamChecksum()...))
+	if err := h.h.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		panic(err)
+	}
+	return append(b, checksum...)
+}
+
+// Reset resets the Hasher to its initial state (an empty sequence).
+func (h *Hasher) Reset() {
+	h.buf = []byte(markerSliceArray)
+	h.empty, h.streaming, h.pending = true, false, nil
+}
+
+// Size returns the number of bytes Sum will return, which is the underlying hash's size.
+func (h *Hasher) Size() int {
+	return h.h.Size()
+}
+
+// BlockSize returns the underlying hash's block size.
+func (h *Hasher) BlockSize() int {
+	return h.h.BlockSize()
+}
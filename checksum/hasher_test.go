@@ -0,0 +1,154 @@
+package checksum
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+var newHashList = []func() hash.Hash{
+	func() hash.Hash { return crc32.NewIEEE() },
+	md5.New, sha1.New, sha256.New, sha512.New, sha512.New512_256,
+	func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ECMA)) },
+	func() hash.Hash { return fnv.New64a() },
+	fnv.New128a,
+	func() hash.Hash { return NewXxHash64() },
+}
+
+var _ hash.Hash = (*Hasher)(nil)
+
+func TestHasher_Empty(t *testing.T) {
+	testName := "TestHasher_Empty"
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			h := NewHasher(newHashList[i]())
+			expected := fmt.Sprintf("%x", hfList[i]([]byte(markerSliceArray)))
+			checksum := fmt.Sprintf("%x", h.Sum(nil))
+			if checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+			if len(h.Sum(nil)) != h.Size() {
+				t.Fatalf("%s failed: expected size %d but received %d", testName+"/"+name, h.Size(), len(h.Sum(nil)))
+			}
+		})
+	}
+}
+
+func TestHasher_WriteValue(t *testing.T) {
+	testName := "TestHasher_WriteValue"
+	now := time.Now()
+	vList := []interface{}{
+		1, "a string", 2.3, true, nil, now, &now,
+		[]int{1, 2, 3},
+		map[string]interface{}{"a": 1, "b": []string{"x", "y"}},
+		MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			h := NewHasher(newHashList[i]())
+			for _, v := range vList {
+				h.WriteValue(v)
+			}
+			expected := fmt.Sprintf("%x", Checksum(hfList[i], vList))
+			checksum := fmt.Sprintf("%x", h.Sum(nil))
+			if checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+			if len(h.Sum(nil)) != h.Size() {
+				t.Fatalf("%s failed: expected size %d but received %d", testName+"/"+name, h.Size(), len(h.Sum(nil)))
+			}
+
+			// Sum must not change the state
+			prefix := []byte("prefix")
+			checksum = fmt.Sprintf("%x", h.Sum(prefix)[len(prefix):])
+			if checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+		})
+	}
+}
+
+func TestHasher_Write(t *testing.T) {
+	testName := "TestHasher_Write"
+	content := strings.Repeat("a line of content\n", 1000)
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			expected := fmt.Sprintf("%x", Checksum(hfList[i], []string{content}))
+
+			// the checksum does not depend on how the stream is split
+			h := NewHasher(newHashList[i]())
+			if n, err := io.Copy(h, strings.NewReader(content)); err != nil || n != int64(len(content)) {
+				t.Fatalf("%s failed: {n: %d / err: %s}", testName+"/"+name, n, err)
+			}
+			if checksum := fmt.Sprintf("%x", h.Sum(nil)); checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+			h = NewHasher(newHashList[i]())
+			for _, line := range strings.SplitAfter(content, "\n") {
+				fmt.Fprintf(h, "%s", line)
+			}
+			if checksum := fmt.Sprintf("%x", h.Sum(nil)); checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+			if len(h.Sum(nil)) != h.Size() {
+				t.Fatalf("%s failed: expected size %d but received %d", testName+"/"+name, h.Size(), len(h.Sum(nil)))
+			}
+		})
+	}
+}
+
+func TestHasher_WriteAndWriteValue(t *testing.T) {
+	testName := "TestHasher_WriteAndWriteValue"
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			h := NewHasher(newHashList[i]())
+			h.WriteValue(1)
+			h.Write([]byte("first "))
+			// Sum in the middle of a stream must not end it
+			h.Sum(nil)
+			h.Write([]byte("stream"))
+			h.WriteValue(true)
+			h.Write([]byte("second stream"))
+			expected := fmt.Sprintf("%x", Checksum(hfList[i], []interface{}{1, "first stream", true, "second stream"}))
+			if checksum := fmt.Sprintf("%x", h.Sum(nil)); checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+			h.Write([]byte("!"))
+			expected = fmt.Sprintf("%x", Checksum(hfList[i], []interface{}{1, "first stream", true, "second stream!"}))
+			if checksum := fmt.Sprintf("%x", h.Sum(nil)); checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+		})
+	}
+}
+
+func TestHasher_Reset(t *testing.T) {
+	testName := "TestHasher_Reset"
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			h := NewHasher(newHashList[i]())
+			h.WriteValue("a string")
+			h.WriteValue(1)
+			h.Reset()
+			h.WriteValue(2)
+			expected := fmt.Sprintf("%x", Checksum(hfList[i], []int{2}))
+			checksum := fmt.Sprintf("%x", h.Sum(nil))
+			if checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+			if h.BlockSize() != newHashList[i]().BlockSize() {
+				t.Fatalf("%s failed: expected block size %d but received %d", testName+"/"+name, newHashList[i]().BlockSize(), h.BlockSize())
+			}
+		})
+	}
+}
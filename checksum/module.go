@@ -7,13 +7,29 @@ Package checksum provides utility functions to calculate checksum.
   - Slice and Array: will have the same checksum. E.g. Checksum([]int{1,2,3}) == Checksum([3]int{1,2,3})
   - Map and Struct: order of fields does not affect checksum, but field names do! E.g. Checksum(map[string]int{"one":1,"two":2}) == Checksum(map[string]int{"two":2,"one":1}), but Checksum(map[string]int{"a":1,"b":2}) != Checksum(map[string]int{"x":1,"y":2})
   - Struct: be able to calculate checksum of unexported fields.
+  - Types implementing Checksummer calculate their own checksums; types implementing encoding.BinaryMarshaler or encoding.TextMarshaler are hashed by their marshaled forms.
+  - Struct: fields tagged with `checksum:"-"` are excluded from checksum calculation; fields tagged with `checksum:"name"` are hashed under "name" instead of their Go names.
 
 Note on special inputs:
 
-  - Checksum of `nil` is a slice where all values are zero.
+  - Checksum of `nil` (or a nil pointer) is a slice where all values are zero.
   - All empty maps have the same checksum, e.g. Checksum(map[string]int{}) == Checksum(map[int]string{}).
   - All empty slices/arrays have the same checksum, e.g. Checksum([]int{}) == Checksum([0]int{}) == Checksum([]string{}) == Checksum([0]string{}).
 
+The rules above are the default preset (DefaultOptions(), the zero value of Options). Use ChecksumWithOptions to change them,
+e.g. ChecksumWithOptions(hf, v, StrictOptions()) calculates type-aware checksums.
+
+Tree calculates checksum as a Merkle tree of the input's map entries, struct fields and slice elements;
+Diff compares two trees and returns the paths whose checksums differ.
+
+Reader, File and FS calculate checksums of content of readers, files and directory trees; StreamReader and StreamFile
+stream content through a hash.Hash instead of loading it into memory.
+
+Canonicalize returns the hash-independent canonical encoding that checksums are calculated from,
+so that implementations in other languages can verify them.
+
+Of is the typed (generic) counterpart of Checksum; Cache memoizes the checksum of a value per caller-provided version key.
+
 Sample usage:
 
 	package main
@@ -0,0 +1,159 @@
+package checksum
+
+import (
+	"reflect"
+	"time"
+)
+
+// SliceOrder specifies whether order and duplicates of elements of slices and arrays affect checksums.
+//
+// @Available since <<VERSION>>
+type SliceOrder int
+
+const (
+	// SliceOrdered: elements are combined in order, e.g. []int{1, 2} and []int{2, 1} have different checksums (the default).
+	SliceOrdered SliceOrder = iota
+
+	// SliceUnordered: elements are combined order-independently, the same way map entries are, but duplicates count
+	// (multiset semantics), e.g. []int{1, 2} and []int{2, 1} have the same checksum, but []int{1, 1, 2} does not.
+	SliceUnordered
+
+	// SliceSet: elements are combined order-independently and duplicates are counted once (set semantics),
+	// e.g. []int{1, 2}, []int{2, 1} and []int{1, 1, 2} have the same checksum.
+	SliceSet
+)
+
+// Options controls how checksum is calculated.
+//
+// The zero value of Options (see DefaultOptions) gives the same result as Checksum.
+type Options struct {
+	// StrictTypes, if true, makes scalar values of different kinds have different checksums,
+	// e.g. int(1), int64(1), uint(1) and float64(1) have different checksums; so do float32(1.5) and float64(1.5).
+	//
+	// Note: only the kind matters, e.g. type MyInt int has the same checksum as int.
+	StrictTypes bool
+
+	// DistinguishNilEmpty, if true, makes nil slices and nil maps have the same checksum as nil, which is different
+	// from checksum of empty slices and empty maps, e.g. Checksum([]int(nil)) != Checksum([]int{}).
+	DistinguishNilEmpty bool
+
+	// IncludeTypeNames, if true, includes type names in checksums of maps, slices and arrays,
+	// e.g. Checksum([]int{}) != Checksum([]string{}) and Checksum([]int{1}) != Checksum([1]int{1}).
+	IncludeTypeNames bool
+
+	// IgnoreUnexported, if true, excludes unexported fields of structs from checksum calculation.
+	IgnoreUnexported bool
+
+	// NormalizeNumbers, if true, makes numbers hash by their numeric values: integral floats have the same checksums
+	// as integers, e.g. float64(1) has the same checksum as int(1), and json.Number values have the same checksums
+	// as the numbers they represent; float32 values are normalized to their shortest decimal forms, the same as they are
+	// encoded in JSON, e.g. float32(0.1) has the same checksum as float64(0.1). Hence, checksum of JSON-compatible data
+	// (nil, bool, numbers, strings, non-nil slices other than []byte and non-nil maps with string keys) does not change
+	// after a JSON round-trip with json.Decoder.UseNumber. Without UseNumber, JSON numbers are decoded as float64, so
+	// integers beyond ±2^53 may lose precision and change checksums.
+	//
+	// Note: nil slices and nil maps are encoded as JSON null and decoded as nil, whose checksum differs from that of empty
+	// slices and maps; also set DistinguishNilEmpty so that they hash as nil before the round-trip too.
+	//
+	// Note: with StrictTypes, numbers are distinguished by their normalized kinds only: integers (int64), unsigned integers
+	// beyond the range of int64 (uint64) and non-integral floats (float64).
+	//
+	// @Available since <<VERSION>>
+	NormalizeNumbers bool
+
+	// NumericStrings, if true, makes strings that are valid JSON numbers (e.g. "1", "-2.5" or "1e3") have the same
+	// checksums as the numbers they represent, e.g. "1" has the same checksum as int(1). NumericStrings implies NormalizeNumbers.
+	//
+	// @Available since <<VERSION>>
+	NumericStrings bool
+
+	// SliceOrder specifies how elements of all slices and arrays are combined, see SliceOrder. Regardless of this option,
+	// a struct field tagged with `checksum:",unordered"` or `checksum:",set"` is hashed as SliceUnordered or SliceSet respectively.
+	//
+	// @Available since <<VERSION>>
+	SliceOrder SliceOrder
+
+	// Workers, if greater than 1, is the maximum number of goroutines used to calculate checksums of elements of large
+	// slices/arrays and entries of large maps in parallel, see ChecksumParallel. The result is the same regardless
+	// of the number of workers.
+	//
+	// @Available since <<VERSION>>
+	Workers int
+
+	// TimePrecision, if positive, truncates time.Time and time.Duration values to multiples of it before calculating
+	// checksums, e.g. with time.Millisecond, times that differ only in microseconds have the same checksum.
+	//
+	// @Available since <<VERSION>>
+	TimePrecision time.Duration
+
+	// IncludeLocation, if true, includes the location (as the offset from UTC, in seconds) of time.Time values in checksums,
+	// e.g. the same instant in UTC and in UTC+7 have different checksums.
+	//
+	// Note: the location's name is not included, as the name of the local time zone depends on the environment.
+	//
+	// @Available since <<VERSION>>
+	IncludeLocation bool
+
+	// DistinguishZeroTime, if true, makes the zero time.Time (see time.Time.IsZero) have a checksum different from all
+	// other times. By default, the checksum of the zero time is calculated from its UnixNano, which is undefined for
+	// times so far from the Unix epoch.
+	//
+	// @Available since <<VERSION>>
+	DistinguishZeroTime bool
+}
+
+// DefaultOptions returns the preset used by Checksum, which is the zero value of Options:
+//   - integers have the same checksum regardless their types, so do floats.
+//   - nil slices and maps have the same checksums as empty ones; all empty maps have the same checksum, so do all empty slices/arrays.
+//   - unexported fields of structs are included.
+//
+// Each call returns a new copy, so modifying the result does not affect other callers.
+func DefaultOptions() Options {
+	return Options{}
+}
+
+// StrictOptions returns a preset for type-aware checksum calculation: values of different types have different
+// checksums, and nil values have different checksums from empty ones.
+//
+// Each call returns a new copy, so modifying the result does not affect other callers.
+func StrictOptions() Options {
+	return Options{StrictTypes: true, DistinguishNilEmpty: true, IncludeTypeNames: true}
+}
+
+// ChecksumWithOptions calculates checksum of an input using the provided hash function and options.
+//
+// See Checksum for how the checksum is calculated; ChecksumWithOptions(hf, v, Options{}) == Checksum(hf, v).
+func ChecksumWithOptions(hf HashFunc, v interface{}, opts Options) []byte {
+	return checksumRoot(newChecksumContext(hf, opts), v)
+}
+
+// ChecksumWithOptionsE is similar to ChecksumWithOptions, but returns an UnsupportedKindError if v contains a value
+// of unsupported kind (chan, func or unsafe.Pointer).
+func ChecksumWithOptionsE(hf HashFunc, v interface{}, opts Options) ([]byte, error) {
+	ctx := newChecksumContext(hf, opts)
+	ctx.trackPath = true
+	checksum := checksumRoot(ctx, v)
+	if ctx.err != nil {
+		return nil, ctx.err
+	}
+	return checksum, nil
+}
+
+// checksumRoot calculates checksum of the input value. A nil pointer has the same checksum as nil.
+func checksumRoot(ctx *checksumContext, v interface{}) []byte {
+	if v == nil {
+		return ctx.nilChecksum()
+	}
+	return checksumRootValue(ctx, reflect.ValueOf(v))
+}
+
+// checksumRootValue is similar to checksumRoot, but takes the input as a reflect.Value.
+func checksumRootValue(ctx *checksumContext, rv reflect.Value) []byte {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ctx.nilChecksum()
+		}
+		rv = rv.Elem()
+	}
+	return checksumValue(ctx, rv)
+}
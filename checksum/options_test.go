@@ -0,0 +1,341 @@
+package checksum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestChecksumWithOptions_Default(t *testing.T) {
+	testName := "TestChecksumWithOptions_Default"
+	vList := []interface{}{
+		nil, 1, "a string", []int{1, 2, 3}, map[string]int{"a": 1},
+		MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, v := range vList {
+				checksum1 := fmt.Sprintf("%x", Checksum(hf, v))
+				checksum2 := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, DefaultOptions()))
+				if checksum1 != checksum2 {
+					t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, v, checksum1, v, checksum2)
+				}
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_IgnoreUnexported(t *testing.T) {
+	testName := "TestChecksumWithOptions_IgnoreUnexported"
+	opts := Options{IgnoreUnexported: true}
+	v1 := MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4}
+	v2 := MyStructPubPriv{S: "string", N: 1, F: 2.3}
+	v3 := MyStructPubPriv{S: "another string", N: 1, F: 2.3}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", ChecksumWithOptions(hf, v1, opts))
+			checksum2 := fmt.Sprintf("%x", ChecksumWithOptions(hf, &v2, opts))
+			checksum3 := fmt.Sprintf("%x", ChecksumWithOptions(hf, v3, opts))
+			if checksum1 != checksum2 {
+				t.Fatalf("%s failed: ChecksumWithOptions(%#v)=%s must be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, v1, checksum1, v2, checksum2)
+			}
+			if checksum1 == checksum3 {
+				t.Fatalf("%s failed: ChecksumWithOptions(%#v)=%s must NOT be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, v1, checksum1, v3, checksum3)
+			}
+			if checksum := fmt.Sprintf("%x", Checksum(hf, v1)); checksum == checksum1 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, v1, checksum, v1, checksum1)
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_StrictTypes(t *testing.T) {
+	testName := "TestChecksumWithOptions_StrictTypes"
+	type MyInt int
+	opts := Options{StrictTypes: true}
+	vArr := []interface{}{int(1), int8(1), int64(1), uint(1), uint64(1), float32(1), float64(1), true, "\x01"}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksumArr := make([]string, len(vArr))
+			for j, v := range vArr {
+				checksumArr[j] = fmt.Sprintf("%x", ChecksumWithOptions(hf, v, opts))
+			}
+			for j := 0; j < len(checksumArr)-1; j++ {
+				for k := j + 1; k < len(checksumArr); k++ {
+					if checksumArr[j] == checksumArr[k] {
+						t.Fatalf("%s failed: ChecksumWithOptions(%#v)=%s must NOT be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, vArr[j], checksumArr[j], vArr[k], checksumArr[k])
+					}
+				}
+			}
+
+			checksum1 := fmt.Sprintf("%x", ChecksumWithOptions(hf, MyInt(1), opts))
+			if checksum1 != checksumArr[0] {
+				t.Fatalf("%s failed: ChecksumWithOptions(%#v)=%s must be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, MyInt(1), checksum1, vArr[0], checksumArr[0])
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_DistinguishNilEmpty(t *testing.T) {
+	testName := "TestChecksumWithOptions_DistinguishNilEmpty"
+	opts := Options{DistinguishNilEmpty: true}
+	vArr := [][2]interface{}{
+		{[]int(nil), []int{}},
+		{map[string]int(nil), map[string]int{}},
+		{struct{ A []int }{}, struct{ A []int }{A: []int{}}},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, pair := range vArr {
+				if checksum1, checksum2 := fmt.Sprintf("%x", Checksum(hf, pair[0])), fmt.Sprintf("%x", Checksum(hf, pair[1])); checksum1 != checksum2 {
+					t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, pair[0], checksum1, pair[1], checksum2)
+				}
+				if checksum1, checksum2 := fmt.Sprintf("%x", ChecksumWithOptions(hf, pair[0], opts)), fmt.Sprintf("%x", ChecksumWithOptions(hf, pair[1], opts)); checksum1 == checksum2 {
+					t.Fatalf("%s failed: ChecksumWithOptions(%#v)=%s must NOT be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, pair[0], checksum1, pair[1], checksum2)
+				}
+			}
+
+			checksumNil := fmt.Sprintf("%x", Checksum(hf, nil))
+			if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, []int(nil), opts)); checksum != checksumNil {
+				t.Fatalf("%s failed: ChecksumWithOptions(%#v)=%s must be the same as Checksum(nil)=%s", testName+"/"+name, []int(nil), checksum, checksumNil)
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_IncludeTypeNames(t *testing.T) {
+	testName := "TestChecksumWithOptions_IncludeTypeNames"
+	opts := Options{IncludeTypeNames: true}
+	vArr := []interface{}{[]int{}, []string{}, [0]int{}, map[string]int{}, map[int]string{}, []int{1}, [1]int{1}}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksumArr := make([]string, len(vArr))
+			for j, v := range vArr {
+				checksumArr[j] = fmt.Sprintf("%x", ChecksumWithOptions(hf, v, opts))
+			}
+			for j := 0; j < len(checksumArr)-1; j++ {
+				for k := j + 1; k < len(checksumArr); k++ {
+					if checksumArr[j] == checksumArr[k] {
+						t.Fatalf("%s failed: ChecksumWithOptions(%#v)=%s must NOT be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, vArr[j], checksumArr[j], vArr[k], checksumArr[k])
+					}
+				}
+			}
+
+			// order of map entries still does not matter
+			checksum1 := fmt.Sprintf("%x", ChecksumWithOptions(hf, map[string]int{"one": 1, "two": 2}, opts))
+			checksum2 := fmt.Sprintf("%x", ChecksumWithOptions(hf, map[string]int{"two": 2, "one": 1}, opts))
+			if checksum1 != checksum2 {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, checksum1, checksum2)
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_StrictOptions(t *testing.T) {
+	testName := "TestChecksumWithOptions_StrictOptions"
+	vArr := []interface{}{[]int{}, map[string]int{}, []int(nil), int(103), uint64(103), float32(10.5), float64(10.5)}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksumArr := make([]string, len(vArr))
+			for j, v := range vArr {
+				checksumArr[j] = fmt.Sprintf("%x", ChecksumWithOptions(hf, v, StrictOptions()))
+			}
+			for j := 0; j < len(checksumArr)-1; j++ {
+				for k := j + 1; k < len(checksumArr); k++ {
+					if checksumArr[j] == checksumArr[k] {
+						t.Fatalf("%s failed: ChecksumWithOptions(%#v)=%s must NOT be the same as ChecksumWithOptions(%#v)=%s", testName+"/"+name, vArr[j], checksumArr[j], vArr[k], checksumArr[k])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_PresetsAreCopies(t *testing.T) {
+	testName := "TestChecksumWithOptions_PresetsAreCopies"
+	expected := fmt.Sprintf("%x", Checksum(Sha256HashFunc, []int{}))
+	opts := DefaultOptions()
+	opts.StrictTypes, opts.IncludeTypeNames = true, true
+	if checksum := fmt.Sprintf("%x", Checksum(Sha256HashFunc, []int{})); checksum != expected {
+		t.Fatalf("%s failed: modifying a preset must not change Checksum, expected %s but received %s", testName, expected, checksum)
+	}
+	if DefaultOptions() != (Options{}) {
+		t.Fatalf("%s failed: DefaultOptions must return the zero Options", testName)
+	}
+	strict := StrictOptions()
+	strict.StrictTypes = false
+	if !StrictOptions().StrictTypes {
+		t.Fatalf("%s failed: modifying a preset must not change the preset", testName)
+	}
+}
+
+func TestChecksumWithOptions_NormalizeNumbers(t *testing.T) {
+	testName := "TestChecksumWithOptions_NormalizeNumbers"
+	testCases := []struct {
+		name      string
+		opts      Options
+		same      []interface{}
+		different []interface{}
+	}{
+		{name: "integral", opts: Options{NormalizeNumbers: true},
+			same:      []interface{}{int(1), int8(1), uint64(1), float32(1), float64(1), json.Number("1"), json.Number("1.0"), json.Number("1e0")},
+			different: []interface{}{"1", float64(1.5), true}},
+		{name: "fraction", opts: Options{NormalizeNumbers: true},
+			same:      []interface{}{float32(-2.5), float64(-2.5), json.Number("-2.5"), json.Number("-25e-1")},
+			different: []interface{}{"-2.5", int(-2), json.Number("-2.50001")}},
+		{name: "negative_zero", opts: Options{NormalizeNumbers: true},
+			same:      []interface{}{0, math.Copysign(0, -1), json.Number("-0")},
+			different: []interface{}{"0", nil}},
+		{name: "strict", opts: Options{NormalizeNumbers: true, StrictTypes: true},
+			same:      []interface{}{int(1), int8(1), uint(1), float32(1), float64(1), json.Number("1")},
+			different: []interface{}{"1", true}},
+		{name: "numeric_strings", opts: Options{NumericStrings: true},
+			same:      []interface{}{"100", "1e2", "100.0", float64(100), int(100), json.Number("100")},
+			different: []interface{}{"100a", "0x64", "+100", "100.", "Inf", "1e1000"}},
+		{name: "big_uint", opts: Options{NormalizeNumbers: true},
+			same:      []interface{}{uint64(math.MaxUint64), json.Number("18446744073709551615")},
+			different: []interface{}{float64(math.MaxUint64), int64(math.MaxInt64)}},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, testCase := range testCases {
+				checksum0 := fmt.Sprintf("%x", ChecksumWithOptions(hf, testCase.same[0], testCase.opts))
+				for _, v := range testCase.same[1:] {
+					if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, testCase.opts)); checksum != checksum0 {
+						t.Fatalf("%s failed: checksum of %#v=%s must be the same as checksum of %#v=%s", testName+"/"+name+"/"+testCase.name, v, checksum, testCase.same[0], checksum0)
+					}
+				}
+				for _, v := range testCase.different {
+					if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, testCase.opts)); checksum == checksum0 {
+						t.Fatalf("%s failed: checksum of %#v=%s must NOT be the same as checksum of %#v=%s", testName+"/"+name+"/"+testCase.name, v, checksum, testCase.same[0], checksum0)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_NormalizeNumbers_JSONRoundTrip(t *testing.T) {
+	testName := "TestChecksumWithOptions_NormalizeNumbers_JSONRoundTrip"
+	opts := Options{NormalizeNumbers: true}
+	v := map[string]interface{}{
+		"id": 12345, "price": 9.99, "qty": uint8(3), "active": true, "tags": []string{"a", "b"}, "nothing": nil,
+		"nested": map[string]interface{}{"big": int64(1) << 52, "negative": -7, "ratio": float32(0.5), "list": []interface{}{1, 2.5, "x"}},
+		"f32":    []float32{0.1, 1.1, -3.3e-7, 16777216, 3.4e38},
+	}
+	js, _ := json.Marshal(v)
+	var decoded interface{}
+	_ = json.Unmarshal(js, &decoded)
+	var decodedNumbers interface{}
+	decoder := json.NewDecoder(bytes.NewReader(js))
+	decoder.UseNumber()
+	_ = decoder.Decode(&decodedNumbers)
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			expected := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, opts))
+			if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, decoded, opts)); checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+			if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, decodedNumbers, opts)); checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s (UseNumber)", testName+"/"+name, expected, checksum)
+			}
+			if checksum := fmt.Sprintf("%x", Checksum(hf, decoded)); checksum == fmt.Sprintf("%x", Checksum(hf, v)) {
+				t.Fatalf("%s failed: checksum of JSON round-tripped value is not expected to match without NormalizeNumbers", testName+"/"+name)
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_NormalizeNumbers_JSONRoundTripNil(t *testing.T) {
+	testName := "TestChecksumWithOptions_NormalizeNumbers_JSONRoundTripNil"
+	v := map[string]interface{}{
+		"nilSlice": []int(nil), "nilMap": map[string]int(nil), "emptySlice": []string{}, "emptyMap": map[string]int{},
+		"nested": []interface{}{[]float64(nil), 1.5},
+	}
+	js, _ := json.Marshal(v)
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(js))
+	decoder.UseNumber()
+	_ = decoder.Decode(&decoded)
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			// nil slices and maps become JSON null, so they keep their checksums only if they hash as nil
+			opts := Options{NormalizeNumbers: true, DistinguishNilEmpty: true}
+			if expected, checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, opts)), fmt.Sprintf("%x", ChecksumWithOptions(hf, decoded, opts)); checksum != expected {
+				t.Fatalf("%s failed: expected %s but received %s", testName+"/"+name, expected, checksum)
+			}
+			opts = Options{NormalizeNumbers: true}
+			if expected, checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, opts)), fmt.Sprintf("%x", ChecksumWithOptions(hf, decoded, opts)); checksum == expected {
+				t.Fatalf("%s failed: nil slices are not expected to keep their checksums without DistinguishNilEmpty", testName+"/"+name)
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_NumericStrings_MapKeys(t *testing.T) {
+	testName := "TestChecksumWithOptions_NumericStrings_MapKeys"
+	opts := Options{NumericStrings: true}
+	v := map[int]string{1: "one", 2: "two"}
+	js, _ := json.Marshal(v)
+	var decoded map[string]interface{}
+	_ = json.Unmarshal(js, &decoded)
+	expected := fmt.Sprintf("%x", ChecksumWithOptions(Sha256HashFunc, v, opts))
+	if checksum := fmt.Sprintf("%x", ChecksumWithOptions(Sha256HashFunc, decoded, opts)); checksum != expected {
+		t.Fatalf("%s failed: expected %s but received %s", testName, expected, checksum)
+	}
+}
+
+func TestChecksumWithOptions_SliceOrder(t *testing.T) {
+	testName := "TestChecksumWithOptions_SliceOrder"
+	testCases := []struct {
+		name      string
+		opts      Options
+		same      []interface{}
+		different []interface{}
+	}{
+		{name: "ordered", opts: Options{},
+			same:      []interface{}{[]int{1, 2, 3}, [3]int{1, 2, 3}, []interface{}{1, 2, 3}},
+			different: []interface{}{[]int{3, 2, 1}, []int{1, 2, 3, 3}}},
+		{name: "unordered", opts: Options{SliceOrder: SliceUnordered},
+			same:      []interface{}{[]int{1, 2, 3}, [3]int{3, 1, 2}, []interface{}{2, 3, 1}},
+			different: []interface{}{[]int{1, 2}, []int{1, 2, 3, 3}, []int{1, 2, 4}, map[int]int{1: 1, 2: 2, 3: 3}}},
+		{name: "set", opts: Options{SliceOrder: SliceSet},
+			same:      []interface{}{[]int{1, 2, 3}, []int{3, 1, 2, 2, 1}},
+			different: []interface{}{[]int{1, 2}, []int{1, 2, 4}}},
+		{name: "nested", opts: Options{SliceOrder: SliceUnordered},
+			same:      []interface{}{[][]int{{1, 2}, {3}}, [][]int{{3}, {2, 1}}},
+			different: []interface{}{[][]int{{1, 2, 3}}, [][]int{{1}, {2, 3}}}},
+		{name: "type_names", opts: Options{SliceOrder: SliceSet, IncludeTypeNames: true},
+			same:      []interface{}{[]int{1, 2}, []int{2, 1, 1}},
+			different: []interface{}{[]int64{1, 2}, [2]int{1, 2}}},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, testCase := range testCases {
+				checksum0 := fmt.Sprintf("%x", ChecksumWithOptions(hf, testCase.same[0], testCase.opts))
+				for _, v := range testCase.same[1:] {
+					if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, testCase.opts)); checksum != checksum0 {
+						t.Fatalf("%s failed: checksum of %#v=%s must be the same as checksum of %#v=%s", testName+"/"+name+"/"+testCase.name, v, checksum, testCase.same[0], checksum0)
+					}
+				}
+				for _, v := range testCase.different {
+					if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, testCase.opts)); checksum == checksum0 {
+						t.Fatalf("%s failed: checksum of %#v=%s must NOT be the same as checksum of %#v=%s", testName+"/"+name+"/"+testCase.name, v, checksum, testCase.same[0], checksum0)
+					}
+				}
+			}
+		})
+	}
+}
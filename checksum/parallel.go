@@ -0,0 +1,107 @@
+package checksum
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelThreshold is the minimum number of elements of a slice/array, or entries of a map, to be processed in parallel.
+const parallelThreshold = 64
+
+// ChecksumParallel calculates checksum of an input using the provided hash function, processing elements of large
+// slices/arrays and entries of large maps with up to workers goroutines.
+//
+// The result is exactly the same as Checksum(hf, v). The hash function (and Checksum methods of values implementing
+// Checksummer) must be safe for concurrent use; all built-in hash functions are.
+//
+// Only the outermost large slices/arrays/maps are processed in parallel, values nested in them are processed sequentially
+// by the workers. Use ChecksumWithOptions with Options.Workers to combine parallel processing with other options.
+//
+// @Available since <<VERSION>>
+func ChecksumParallel(hf HashFunc, v interface{}, workers int) []byte {
+	return ChecksumWithOptions(hf, v, Options{Workers: workers})
+}
+
+// parallel checks if n elements/entries should be processed in parallel.
+func (ctx *checksumContext) parallel(n int) bool {
+	return ctx.opts.Workers > 1 && !ctx.inWorker && ctx.node == nil && n >= parallelThreshold
+}
+
+// fork creates a context for a worker of a parallel calculation. The worker has its own copies of the state.
+func (ctx *checksumContext) fork() *checksumContext {
+	wctx := newChecksumContext(ctx.hf, ctx.opts)
+	for ptr := range ctx.visited {
+		wctx.visited[ptr] = struct{}{}
+	}
+	wctx.trackPath = ctx.trackPath
+	wctx.path = append([]string{}, ctx.path...)
+	wctx.inWorker = true
+	return wctx
+}
+
+// checksumParallel calculates checksums of n items using up to ctx.opts.Workers goroutines,
+// where item(wctx, i) calculates checksum of the i-th item using the worker's own context.
+func checksumParallel(ctx *checksumContext, n int, item func(wctx *checksumContext, i int) []byte) [][]byte {
+	workers := ctx.opts.Workers
+	if workers > n {
+		workers = n
+	}
+	checksums := make([][]byte, n)
+	errIndexes := make([]int, workers) // index of the item at which each worker encountered its first error
+	wctxList := make([]*checksumContext, workers)
+	var next int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wctx := ctx.fork()
+		wctxList[w] = wctx
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for {
+				i := int(atomic.AddInt64(&next, 1) - 1)
+				if i >= n {
+					return
+				}
+				hasErr := wctx.err != nil
+				checksums[i] = item(wctx, i)
+				if !hasErr && wctx.err != nil {
+					errIndexes[w] = i
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// report the error of the first item, the same as sequential processing does
+	if ctx.err == nil {
+		errIndex := n
+		for w, wctx := range wctxList {
+			if wctx.err != nil && errIndexes[w] < errIndex {
+				ctx.err, errIndex = wctx.err, errIndexes[w]
+			}
+		}
+	}
+	return checksums
+}
+
+// parallelElements calculates checksums of elements of a slice/array in parallel.
+func parallelElements(ctx *checksumContext, rv reflect.Value) [][]byte {
+	return checksumParallel(ctx, rv.Len(), func(wctx *checksumContext, i int) []byte {
+		return checksumChild(wctx, pathSegment{index: i}, rv.Index(i))
+	})
+}
+
+// parallelEntries calculates checksums of entries of a map in parallel.
+func parallelEntries(ctx *checksumContext, rv reflect.Value) [][]byte {
+	keys := make([]reflect.Value, 0, rv.Len())
+	values := make([]reflect.Value, 0, rv.Len())
+	for iter := rv.MapRange(); iter.Next(); {
+		keys = append(keys, iter.Key())
+		values = append(values, iter.Value())
+	}
+	return checksumParallel(ctx, len(keys), func(wctx *checksumContext, i int) []byte {
+		segment := pathSegment{key: keys[i]}
+		return wctx.fold(checksumMapKey(wctx, segment, keys[i]), checksumChild(wctx, segment, values[i]))
+	})
+}
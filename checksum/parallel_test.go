@@ -0,0 +1,118 @@
+package checksum
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newParallelTestValues() []interface{} {
+	now := time.Now()
+	bigMap := make(map[string]interface{})
+	bigSlice := make([]interface{}, 0, 200)
+	for i := 0; i < 200; i++ {
+		bigMap["key"+strconv.Itoa(i)] = map[string]interface{}{"i": i, "s": []int{i, i + 1}, "t": now}
+		bigSlice = append(bigSlice, newBenchmarkStruct(i))
+	}
+	bigArray := [100]int{}
+	for i := range bigArray {
+		bigArray[i] = i * i
+	}
+	node := &Node{Value: 1}
+	node.Next = node
+	circular := make([]interface{}, 100)
+	for i := range circular {
+		circular[i] = node
+	}
+	circularMap := make(map[int]interface{})
+	for i := 0; i < 100; i++ {
+		circularMap[i] = circularMap
+	}
+	return []interface{}{
+		nil, 1, "a string", []int{1, 2, 3}, bigMap, &bigMap, bigSlice, bigArray, circular, circularMap,
+		map[string]interface{}{"nested": bigMap, "slice": bigSlice},
+	}
+}
+
+func TestChecksumParallel(t *testing.T) {
+	testName := "TestChecksumParallel"
+	vList := newParallelTestValues()
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for j, v := range vList {
+				expected := Checksum(hf, v)
+				for _, workers := range []int{0, 1, 2, 7, 64} {
+					if checksum := ChecksumParallel(hf, v, workers); !reflect.DeepEqual(checksum, expected) {
+						t.Fatalf("%s failed: value #%d with %d workers: expected %x but received %x", testName+"/"+name, j, workers, expected, checksum)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_Workers(t *testing.T) {
+	testName := "TestChecksumWithOptions_Workers"
+	vList := newParallelTestValues()
+	optsList := []Options{StrictOptions(), {SliceOrder: SliceUnordered}, {SliceOrder: SliceSet, IncludeTypeNames: true}, {NormalizeNumbers: true, IgnoreUnexported: true}}
+	for _, opts := range optsList {
+		t.Run(fmt.Sprintf("%+v", opts), func(t *testing.T) {
+			for _, v := range vList {
+				expected := ChecksumWithOptions(Sha256HashFunc, v, opts)
+				opts.Workers = 4
+				if checksum := ChecksumWithOptions(Sha256HashFunc, v, opts); !reflect.DeepEqual(checksum, expected) {
+					t.Fatalf("%s failed: expected %x but received %x", testName, expected, checksum)
+				}
+				opts.Workers = 0
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptionsE_Workers(t *testing.T) {
+	testName := "TestChecksumWithOptionsE_Workers"
+	v := make([]interface{}, 200)
+	for i := range v {
+		v[i] = i
+	}
+	v[150] = make(chan int)
+	v[120] = map[string]interface{}{"f": func() {}}
+	_, err := ChecksumWithOptionsE(Sha256HashFunc, v, Options{Workers: 8})
+	var ukErr *UnsupportedKindError
+	if !errors.As(err, &ukErr) {
+		t.Fatalf("%s failed: expected UnsupportedKindError but received %#v", testName, err)
+	}
+	if expected := "[120].f"; ukErr.Path != expected {
+		t.Fatalf("%s failed: expected path %#v but received %#v", testName, expected, ukErr.Path)
+	}
+}
+
+func newParallelBenchmarkMap() map[string]interface{} {
+	v := make(map[string]interface{}, 1000)
+	for i := 0; i < 1000; i++ {
+		v["key-"+strconv.Itoa(i)] = newBenchmarkStruct(i)
+	}
+	return v
+}
+
+func BenchmarkChecksumParallel_MapOfStructs(b *testing.B) {
+	v := newParallelBenchmarkMap()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ChecksumParallel(Sha256HashFunc, v, 8)
+	}
+}
+
+func BenchmarkChecksumSequential_MapOfStructs(b *testing.B) {
+	v := newParallelBenchmarkMap()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Checksum(Sha256HashFunc, v)
+	}
+}
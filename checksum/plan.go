@@ -0,0 +1,145 @@
+package checksum
+
+import (
+	"reflect"
+	"sync"
+)
+
+// implKind tells whether a type implements an interface: not at all, via its value, or via its pointer only.
+type implKind int
+
+const (
+	implNone implKind = iota
+	implValue
+	implPointer
+)
+
+func implKindOf(t, iface reflect.Type) implKind {
+	if t.Implements(iface) {
+		return implValue
+	}
+	if reflect.PtrTo(t).Implements(iface) {
+		return implPointer
+	}
+	return implNone
+}
+
+// declaredImplKindOf is similar to implKindOf, but ignores methods that a struct type may only have because they are
+// promoted from an embedded field, e.g. struct{ time.Time; Name string } is not treated as an encoding.BinaryMarshaler:
+// otherwise the embedded field's method would determine the whole checksum and the other fields would be ignored.
+//
+// Reflection cannot tell a method declared on the struct type from a promoted one, so a struct type is hashed field by
+// field whenever an embedded field provides the method, even if the struct type re-declares it.
+func declaredImplKindOf(t, iface reflect.Type) implKind {
+	kind := implKindOf(t, iface)
+	if kind != implNone && t.Kind() == reflect.Struct && hasEmbeddedMethod(t, iface) {
+		return implNone
+	}
+	return kind
+}
+
+// hasEmbeddedMethod checks if the method set of an embedded field of a struct type (or of its pointer, as an embedded
+// field of an addressable struct is addressable) provides a method of the interface.
+func hasEmbeddedMethod(t, iface reflect.Type) bool {
+	for i, n := 0, t.NumField(); i < n; i++ {
+		field := t.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		for j, m := 0, iface.NumMethod(); j < m; j++ {
+			name := iface.Method(j).Name
+			if _, ok := field.Type.MethodByName(name); ok {
+				return true
+			}
+			if field.Type.Kind() != reflect.Ptr && field.Type.Kind() != reflect.Interface {
+				if _, ok := reflect.PtrTo(field.Type).MethodByName(name); ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// fieldPlan is the pre-computed information of a struct field.
+type fieldPlan struct {
+	index    int
+	name     string // name used to calculate checksum: the name from the `checksum` tag, or the field name
+	exported bool
+
+	sliceOrder SliceOrder // slice order specified by the `checksum` tag
+}
+
+// typePlan is the pre-computed information used to calculate checksum of values of a type.
+//
+// Plans are computed once per type and cached, so that checksum calculation does not need to reflect through
+// methods, struct fields and tags on every call.
+type typePlan struct {
+	typeName string
+
+	hasCustom       bool // true if values of the type may define their own canonical forms, see customChecksum
+	checksummer     implKind
+	legacyMethod    int  // index of the legacy "Checksum()" method of structs, -1 if none
+	legacyOnPointer bool // true if the legacy method is declared on the pointer type
+	isTime          bool // time.Time, or a named type of time.Time (e.g. type MyTime time.Time)
+	isDuration      bool
+	isJSONNumber    bool
+	binaryMarshaler implKind
+	textMarshaler   implKind
+
+	fields        []fieldPlan // struct fields, excluding those tagged with `checksum:"-"`
+	hasUnexported bool
+}
+
+var typePlans sync.Map // map[reflect.Type]*typePlan
+
+// typePlanOf returns the (cached) plan of a type.
+func typePlanOf(t reflect.Type) *typePlan {
+	if plan, ok := typePlans.Load(t); ok {
+		return plan.(*typePlan)
+	}
+	plan, _ := typePlans.LoadOrStore(t, newTypePlan(t))
+	return plan.(*typePlan)
+}
+
+func newTypePlan(t reflect.Type) *typePlan {
+	plan := &typePlan{
+		typeName:        t.String(),
+		checksummer:     declaredImplKindOf(t, typeChecksummer),
+		legacyMethod:    -1,
+		isTime:          t == typeTime || (t.Kind() == reflect.Struct && t.ConvertibleTo(typeTime)),
+		isDuration:      t == typeDuration,
+		isJSONNumber:    t == typeJSONNumber,
+		binaryMarshaler: declaredImplKindOf(t, typeBinaryMarshaler),
+		textMarshaler:   declaredImplKindOf(t, typeTextMarshaler),
+	}
+	if t.Kind() == reflect.Struct {
+		// the legacy method is looked up on the value first; the pointer is looked up only if the value does not have the method
+		if m, ok := t.MethodByName("Checksum"); ok {
+			if m.Type.NumIn() == 1 {
+				plan.legacyMethod = m.Index
+			}
+		} else if m, ok := reflect.PtrTo(t).MethodByName("Checksum"); ok && m.Type.NumIn() == 1 {
+			plan.legacyMethod, plan.legacyOnPointer = m.Index, true
+		}
+
+		for i, n := 0, t.NumField(); i < n; i++ {
+			field := t.Field(i)
+			fieldPlan := fieldPlan{index: i, name: field.Name, exported: field.PkgPath == ""}
+			if tag, ok := field.Tag.Lookup("checksum"); ok {
+				if tag == "-" {
+					continue
+				}
+				if tagName := tagName(tag); tagName != "" {
+					fieldPlan.name = tagName
+				}
+				fieldPlan.sliceOrder = tagSliceOrder(tag)
+			}
+			plan.hasUnexported = plan.hasUnexported || !fieldPlan.exported
+			plan.fields = append(plan.fields, fieldPlan)
+		}
+	}
+	plan.hasCustom = plan.checksummer != implNone || plan.legacyMethod >= 0 || plan.isTime ||
+		plan.binaryMarshaler != implNone || plan.textMarshaler != implNone
+	return plan
+}
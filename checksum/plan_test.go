@@ -0,0 +1,63 @@
+package checksum
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type planTestStruct struct {
+	Name    string `checksum:"name"`
+	Cache   []byte `checksum:"-"`
+	private int
+}
+
+func TestTypePlanOf(t *testing.T) {
+	testName := "TestTypePlanOf"
+	plan := typePlanOf(reflect.TypeOf(planTestStruct{}))
+	if plan != typePlanOf(reflect.TypeOf(planTestStruct{})) {
+		t.Fatalf("%s failed: plan is not cached", testName)
+	}
+	if plan.typeName != "checksum.planTestStruct" {
+		t.Fatalf("%s failed: expected type name %#v but received %#v", testName, "checksum.planTestStruct", plan.typeName)
+	}
+	expected := []fieldPlan{{index: 0, name: "name", exported: true}, {index: 2, name: "private", exported: false}}
+	if len(plan.fields) != len(expected) {
+		t.Fatalf("%s failed: expected %d fields but received %d", testName, len(expected), len(plan.fields))
+	}
+	for i, field := range plan.fields {
+		if field != expected[i] {
+			t.Fatalf("%s failed: expected field %#v but received %#v", testName, expected[i], field)
+		}
+	}
+	if !plan.hasUnexported || plan.hasCustom {
+		t.Fatalf("%s failed: unexpected plan %#v", testName, plan)
+	}
+}
+
+func TestTypePlanOf_Custom(t *testing.T) {
+	testName := "TestTypePlanOf_Custom"
+	testCases := []struct {
+		name            string
+		v               interface{}
+		checksummer     implKind
+		legacyMethod    bool
+		legacyOnPointer bool
+		isTime          bool
+	}{
+		{name: "checksummer", v: MyChecksummer{}, checksummer: implValue},
+		{name: "checksummer_ptr", v: MyChecksummerPtr{}, checksummer: implPointer},
+		{name: "legacy", v: MyStructCustom1{}, legacyMethod: true},
+		{name: "time", v: time.Time{}, isTime: true},
+		{name: "named_time", v: MyTime{}, isTime: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			plan := typePlanOf(reflect.TypeOf(testCase.v))
+			if !plan.hasCustom || plan.checksummer != testCase.checksummer || (plan.legacyMethod >= 0) != testCase.legacyMethod ||
+				plan.legacyOnPointer != testCase.legacyOnPointer || plan.isTime != testCase.isTime {
+				t.Fatalf("%s failed: unexpected plan %#v", testName+"/"+testCase.name, plan)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package checksum
+
+import (
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Reader calculates checksum of all content read from r, until EOF.
+//
+// The result is the same as checksum of the content as a string, i.e. Reader(hf, strings.NewReader(s)) == Checksum(hf, s).
+//
+// Note: as a HashFunc takes its whole input at once, the content is loaded into memory; use StreamReader to calculate
+// checksum of large content with constant memory.
+//
+// @Available since <<VERSION>>
+func Reader(hf HashFunc, r io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return hf(data), nil
+}
+
+// File calculates checksum of content of a file, see Reader.
+//
+// Note: the whole file is loaded into memory; use StreamFile for large files.
+//
+// @Available since <<VERSION>>
+func File(hf HashFunc, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Reader(hf, f)
+}
+
+// StreamReader calculates checksum of all content read from r, until EOF, by streaming the content through a hash
+// created by newHash, so that the content is never held in memory as a whole.
+//
+// The result is the same as Reader with a HashFunc of the same algorithm,
+// e.g. StreamReader(sha256.New, r) == Reader(Sha256HashFunc, r).
+//
+// @Available since <<VERSION>>
+func StreamReader(newHash func() hash.Hash, r io.Reader) ([]byte, error) {
+	h := newHash()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// StreamFile calculates checksum of content of a file, see StreamReader.
+//
+// @Available since <<VERSION>>
+func StreamFile(newHash func() hash.Hash, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return StreamReader(newHash, f)
+}
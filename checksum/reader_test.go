@@ -0,0 +1,114 @@
+package checksum
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type errReader struct{}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, errors.New("read error")
+}
+
+func TestReader(t *testing.T) {
+	testName := "TestReader"
+	content := strings.Repeat("a line of content\n", 1000)
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum, err := Reader(hf, strings.NewReader(content))
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+name, err)
+			}
+			if expected := Checksum(hf, content); !reflect.DeepEqual(checksum, expected) {
+				t.Fatalf("%s failed: expected %x but received %x", testName+"/"+name, expected, checksum)
+			}
+		})
+	}
+	if _, err := Reader(Sha256HashFunc, errReader{}); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+}
+
+func TestFile(t *testing.T) {
+	testName := "TestFile"
+	dir, err := ioutil.TempDir("", "checksum")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	defer os.RemoveAll(dir)
+	content := "file content"
+	path := filepath.Join(dir, "file.txt")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum, err := File(hf, path)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+name, err)
+			}
+			if expected := Checksum(hf, content); !reflect.DeepEqual(checksum, expected) {
+				t.Fatalf("%s failed: expected %x but received %x", testName+"/"+name, expected, checksum)
+			}
+		})
+	}
+	if _, err := File(Sha256HashFunc, filepath.Join(dir, "not-exist")); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+}
+
+func TestStreamReader(t *testing.T) {
+	testName := "TestStreamReader"
+	content := strings.Repeat("a line of content\n", 10000)
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			checksum, err := StreamReader(newHashList[i], strings.NewReader(content))
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+name, err)
+			}
+			if expected, _ := Reader(hfList[i], strings.NewReader(content)); !reflect.DeepEqual(checksum, expected) {
+				t.Fatalf("%s failed: expected %x but received %x", testName+"/"+name, expected, checksum)
+			}
+		})
+	}
+	if _, err := StreamReader(sha256.New, errReader{}); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+}
+
+func TestStreamFile(t *testing.T) {
+	testName := "TestStreamFile"
+	dir, err := ioutil.TempDir("", "checksum")
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	defer os.RemoveAll(dir)
+	content := strings.Repeat("file content", 10000)
+	path := filepath.Join(dir, "file.txt")
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			checksum, err := StreamFile(newHashList[i], path)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+name, err)
+			}
+			if expected, _ := File(hfList[i], path); !reflect.DeepEqual(checksum, expected) {
+				t.Fatalf("%s failed: expected %x but received %x", testName+"/"+name, expected, checksum)
+			}
+		})
+	}
+	if _, err := StreamFile(sha256.New, filepath.Join(dir, "not-exist")); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+}
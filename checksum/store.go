@@ -0,0 +1,293 @@
+package checksum
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNotFound is returned by Store.Get if the store does not have the requested object.
+	//
+	// @Available since <<VERSION>>
+	ErrNotFound = errors.New("object not found")
+
+	// ErrIntegrity is returned by Store.Get if the stored object does not match its digest, e.g. the stored data was corrupted.
+	//
+	// @Available since <<VERSION>>
+	ErrIntegrity = errors.New("object integrity check failed")
+)
+
+// storeOptions is the options used to calculate digests of stored objects: numbers are normalized, so that the digest of
+// a value is the same as the digest of its JSON round-tripped form.
+var storeOptions = Options{NormalizeNumbers: true}
+
+// Store is a content-addressable store: objects are serialized as JSON and keyed by their digests, so that storing the
+// same object (or equal objects) twice keeps only one copy.
+//
+// The digest of an object is the checksum of its JSON form decoded into a generic tree (maps, slices, strings, numbers,
+// booleans and nulls), with numbers normalized (see Options.NormalizeNumbers). Hence an object and its JSON round-tripped
+// form share the same digest, e.g. a struct and the map[string]interface{} decoded from its JSON.
+//
+// @Available since <<VERSION>>
+type Store interface {
+	// Put stores an object and returns its digest. Storing an object that already exists is a no-op.
+	Put(v interface{}) (Digest, error)
+
+	// Get loads the object identified by the digest into out (as per json.Unmarshal). The stored data is verified against
+	// the digest: ErrIntegrity is returned if they do not match; ErrNotFound is returned if the object does not exist.
+	Get(d Digest, out interface{}) error
+
+	// Has checks if the store has the object identified by the digest.
+	Has(d Digest) (bool, error)
+
+	// GC removes all objects that are not in the referenced list, and returns the number of removed objects.
+	GC(referenced []Digest) (int, error)
+}
+
+// storeDigest serializes an object as JSON and calculates its digest.
+func storeDigest(alg string, hf HashFunc, v interface{}) (Digest, []byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return Digest{}, nil, err
+	}
+	d, err := jsonDigest(alg, hf, data)
+	return d, data, err
+}
+
+// jsonDigest calculates digest of a JSON document, decoded into a generic tree.
+func jsonDigest(alg string, hf HashFunc, data []byte) (Digest, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return Digest{}, err
+	}
+	return Digest{Algorithm: alg, Sum: ChecksumWithOptions(hf, tree, storeOptions)}, nil
+}
+
+// verifyAndUnmarshal verifies stored data against the digest, then unmarshals it into out.
+func verifyAndUnmarshal(alg string, hf HashFunc, d Digest, data []byte, out interface{}) error {
+	actual, err := jsonDigest(alg, hf, data)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrIntegrity, err)
+	}
+	if !actual.Equal(d) {
+		return fmt.Errorf("%w: expected %s but stored data has digest %s", ErrIntegrity, d, actual)
+	}
+	return json.Unmarshal(data, out)
+}
+
+// referencedSet returns the set of referenced digests, in their string forms.
+func referencedSet(referenced []Digest) map[string]bool {
+	result := make(map[string]bool, len(referenced))
+	for _, d := range referenced {
+		result[d.String()] = true
+	}
+	return result
+}
+
+/*----------------------------------------------------------------------*/
+
+// MemoryStore is an in-memory Store, safe for concurrent use.
+//
+// @Available since <<VERSION>>
+type MemoryStore struct {
+	alg     string
+	hf      HashFunc
+	lock    sync.RWMutex
+	objects map[string][]byte // JSON data of objects, keyed by the string forms of their digests
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new in-memory Store that calculates digests using the algorithm registered under alg.
+//
+// @Available since <<VERSION>>
+func NewMemoryStore(alg string) (*MemoryStore, error) {
+	hf, err := lookupAlgorithm(alg)
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryStore{alg: alg, hf: hf, objects: make(map[string][]byte)}, nil
+}
+
+// Put implements Store.Put.
+func (s *MemoryStore) Put(v interface{}) (Digest, error) {
+	d, data, err := storeDigest(s.alg, s.hf, v)
+	if err != nil {
+		return Digest{}, err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.objects[d.String()]; !ok {
+		s.objects[d.String()] = data
+	}
+	return d, nil
+}
+
+// Get implements Store.Get.
+func (s *MemoryStore) Get(d Digest, out interface{}) error {
+	s.lock.RLock()
+	data, ok := s.objects[d.String()]
+	s.lock.RUnlock()
+	if !ok {
+		return ErrNotFound
+	}
+	return verifyAndUnmarshal(s.alg, s.hf, d, data, out)
+}
+
+// Has implements Store.Has.
+func (s *MemoryStore) Has(d Digest) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	_, ok := s.objects[d.String()]
+	return ok, nil
+}
+
+// GC implements Store.GC.
+func (s *MemoryStore) GC(referenced []Digest) (int, error) {
+	keep := referencedSet(referenced)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	removed := 0
+	for key := range s.objects {
+		if !keep[key] {
+			delete(s.objects, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// DirStore is a Store backed by a directory: each object is stored as a JSON file at
+// <dir>/<algorithm>/<first 2 hex digits of the digest>/<hex-encoded digest>.json.
+//
+// Files are written atomically (to a temporary file, then renamed), so that concurrent writers and readers (even from
+// different processes) never see partially written objects.
+//
+// @Available since <<VERSION>>
+type DirStore struct {
+	alg string
+	hf  HashFunc
+	dir string
+}
+
+var _ Store = (*DirStore)(nil)
+
+// NewDirStore creates a new Store backed by the directory dir, which is created if it does not exist.
+// Digests are calculated using the algorithm registered under alg.
+//
+// @Available since <<VERSION>>
+func NewDirStore(dir, alg string) (*DirStore, error) {
+	hf, err := lookupAlgorithm(alg)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, alg), 0755); err != nil {
+		return nil, err
+	}
+	return &DirStore{alg: alg, hf: hf, dir: dir}, nil
+}
+
+// path returns path of the file storing the object identified by the digest, or "" if the digest does not belong to the store.
+func (s *DirStore) path(d Digest) string {
+	if d.Algorithm != s.alg || len(d.Sum) == 0 {
+		return ""
+	}
+	h := d.Hex()
+	return filepath.Join(s.dir, s.alg, h[:2], h+".json")
+}
+
+// Put implements Store.Put.
+func (s *DirStore) Put(v interface{}) (Digest, error) {
+	d, data, err := storeDigest(s.alg, s.hf, v)
+	if err != nil {
+		return Digest{}, err
+	}
+	path := s.path(d)
+	if _, err := os.Stat(path); err == nil {
+		return d, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return Digest{}, err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return Digest{}, err
+	}
+	_, err = f.Write(data)
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return Digest{}, err
+	}
+	return d, nil
+}
+
+// Get implements Store.Get.
+func (s *DirStore) Get(d Digest, out interface{}) error {
+	path := s.path(d)
+	if path == "" {
+		return ErrNotFound
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return verifyAndUnmarshal(s.alg, s.hf, d, data, out)
+}
+
+// Has implements Store.Has.
+func (s *DirStore) Has(d Digest) (bool, error) {
+	path := s.path(d)
+	if path == "" {
+		return false, nil
+	}
+	_, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GC implements Store.GC.
+func (s *DirStore) GC(referenced []Digest) (int, error) {
+	keep := make(map[string]bool, len(referenced))
+	for _, d := range referenced {
+		if path := s.path(d); path != "" {
+			keep[path] = true
+		}
+	}
+	removed := 0
+	err := filepath.Walk(filepath.Join(s.dir, s.alg), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".json") || keep[path] {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
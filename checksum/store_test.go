@@ -0,0 +1,214 @@
+package checksum
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+type MyStoreObject struct {
+	ID    int                    `json:"id"`
+	Name  string                 `json:"name"`
+	Tags  []string               `json:"tags"`
+	Props map[string]interface{} `json:"props"`
+}
+
+type storeTestCase struct {
+	name     string
+	newStore func(t *testing.T) (Store, func())
+	corrupt  func(s Store, d Digest, data []byte)
+}
+
+var storeTestCases = []storeTestCase{
+	{
+		name: "memory",
+		newStore: func(t *testing.T) (Store, func()) {
+			s, err := NewMemoryStore(AlgSha256)
+			if err != nil {
+				t.Fatalf("NewMemoryStore failed: %s", err)
+			}
+			return s, func() {}
+		},
+		corrupt: func(s Store, d Digest, data []byte) {
+			ms := s.(*MemoryStore)
+			ms.objects[d.String()] = data
+		},
+	},
+	{
+		name: "dir",
+		newStore: func(t *testing.T) (Store, func()) {
+			dir, err := ioutil.TempDir("", "checksum-store")
+			if err != nil {
+				t.Fatalf("TempDir failed: %s", err)
+			}
+			s, err := NewDirStore(filepath.Join(dir, "store"), AlgSha256)
+			if err != nil {
+				t.Fatalf("NewDirStore failed: %s", err)
+			}
+			return s, func() { os.RemoveAll(dir) }
+		},
+		corrupt: func(s Store, d Digest, data []byte) {
+			_ = ioutil.WriteFile(s.(*DirStore).path(d), data, 0644)
+		},
+	},
+}
+
+func TestStore_PutGet(t *testing.T) {
+	testName := "TestStore_PutGet"
+	obj := MyStoreObject{ID: 1, Name: "object", Tags: []string{"a", "b"}, Props: map[string]interface{}{"x": 1.5, "y": "z"}}
+	for _, testCase := range storeTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, cleanup := testCase.newStore(t)
+			defer cleanup()
+			d, err := s.Put(obj)
+			if err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			if d.Algorithm != AlgSha256 || len(d.Sum) != 32 {
+				t.Fatalf("%s failed: unexpected digest %s", testName+"/"+testCase.name, d)
+			}
+			if ok, err := s.Has(d); err != nil || !ok {
+				t.Fatalf("%s failed: object %s must exist (error: %v)", testName+"/"+testCase.name, d, err)
+			}
+			var loaded MyStoreObject
+			if err := s.Get(d, &loaded); err != nil {
+				t.Fatalf("%s failed: %s", testName+"/"+testCase.name, err)
+			}
+			if !reflect.DeepEqual(loaded, obj) {
+				t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+testCase.name, obj, loaded)
+			}
+
+			// equal objects share the same digest, regardless of their Go types
+			generic := map[string]interface{}{"id": 1, "name": "object", "tags": []interface{}{"a", "b"}, "props": map[string]interface{}{"y": "z", "x": 1.5}}
+			if d2, err := s.Put(generic); err != nil || !d2.Equal(d) {
+				t.Fatalf("%s failed: expected %s but received %s (error: %v)", testName+"/"+testCase.name, d, d2, err)
+			}
+			if d3, err := s.Put(&obj); err != nil || !d3.Equal(d) {
+				t.Fatalf("%s failed: expected %s but received %s (error: %v)", testName+"/"+testCase.name, d, d3, err)
+			}
+		})
+	}
+}
+
+func TestStore_NotFound(t *testing.T) {
+	testName := "TestStore_NotFound"
+	for _, testCase := range storeTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, cleanup := testCase.newStore(t)
+			defer cleanup()
+			dList := []Digest{
+				{Algorithm: AlgSha256, Sum: Sha256Checksum("not-exist")},
+				{Algorithm: AlgMd5, Sum: Md5Checksum("not-exist")},
+				{},
+			}
+			for _, d := range dList {
+				if ok, err := s.Has(d); err != nil || ok {
+					t.Fatalf("%s failed: object %s must not exist (error: %v)", testName+"/"+testCase.name, d, err)
+				}
+				var out interface{}
+				if err := s.Get(d, &out); !errors.Is(err, ErrNotFound) {
+					t.Fatalf("%s failed: expected ErrNotFound but received %#v", testName+"/"+testCase.name, err)
+				}
+			}
+		})
+	}
+}
+
+func TestStore_Integrity(t *testing.T) {
+	testName := "TestStore_Integrity"
+	for _, testCase := range storeTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, cleanup := testCase.newStore(t)
+			defer cleanup()
+			d, _ := s.Put(map[string]interface{}{"amount": 100})
+			for _, data := range []string{`{"amount": 1000}`, `{"amount": `} {
+				testCase.corrupt(s, d, []byte(data))
+				var out map[string]interface{}
+				if err := s.Get(d, &out); !errors.Is(err, ErrIntegrity) {
+					t.Fatalf("%s failed: expected ErrIntegrity but received %#v", testName+"/"+testCase.name, err)
+				}
+			}
+			// formatting differences are not corruptions
+			testCase.corrupt(s, d, []byte("{\n  \"amount\": 100.0\n}"))
+			var out map[string]interface{}
+			if err := s.Get(d, &out); err != nil || out["amount"] != 100.0 {
+				t.Fatalf("%s failed: unexpected %#v (error: %v)", testName+"/"+testCase.name, out, err)
+			}
+		})
+	}
+}
+
+func TestStore_GC(t *testing.T) {
+	testName := "TestStore_GC"
+	for _, testCase := range storeTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, cleanup := testCase.newStore(t)
+			defer cleanup()
+			var dList []Digest
+			for i := 0; i < 10; i++ {
+				d, _ := s.Put(map[string]interface{}{"i": i})
+				dList = append(dList, d)
+			}
+			removed, err := s.GC(dList[:3])
+			if err != nil || removed != 7 {
+				t.Fatalf("%s failed: expected 7 objects removed but received %d (error: %v)", testName+"/"+testCase.name, removed, err)
+			}
+			for i, d := range dList {
+				if ok, _ := s.Has(d); ok != (i < 3) {
+					t.Fatalf("%s failed: existence of object #%d is expected to be %v", testName+"/"+testCase.name, i, i < 3)
+				}
+			}
+			if removed, err := s.GC(nil); err != nil || removed != 3 {
+				t.Fatalf("%s failed: expected 3 objects removed but received %d (error: %v)", testName+"/"+testCase.name, removed, err)
+			}
+		})
+	}
+}
+
+func TestStore_Concurrent(t *testing.T) {
+	testName := "TestStore_Concurrent"
+	for _, testCase := range storeTestCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			s, cleanup := testCase.newStore(t)
+			defer cleanup()
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					for j := 0; j < 20; j++ {
+						d, err := s.Put(map[string]interface{}{"j": j})
+						if err != nil {
+							t.Errorf("%s failed: %s", testName+"/"+testCase.name, err)
+							return
+						}
+						var out map[string]interface{}
+						if err := s.Get(d, &out); err != nil {
+							t.Errorf("%s failed: %s", testName+"/"+testCase.name, err)
+							return
+						}
+					}
+				}(i)
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestStore_Errors(t *testing.T) {
+	testName := "TestStore_Errors"
+	if _, err := NewMemoryStore("unknown"); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+	if _, err := NewDirStore(os.TempDir(), "unknown"); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+	s, _ := NewMemoryStore(AlgMd5)
+	if _, err := s.Put(make(chan int)); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+}
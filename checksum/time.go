@@ -0,0 +1,34 @@
+package checksum
+
+import (
+	"reflect"
+	"time"
+)
+
+// timeChecksum calculates checksum of a time.Time value, taking TimePrecision, IncludeLocation and DistinguishZeroTime into account.
+//
+// Named types of time.Time are converted to time.Time before calling this function, so they share checksums with time.Time.
+func (ctx *checksumContext) timeChecksum(t time.Time) []byte {
+	checksums := [][]byte{ctx.nameChecksum(markerStruct), ctx.nameChecksum(typeTime.String())}
+	if ctx.opts.DistinguishZeroTime && t.IsZero() {
+		return ctx.fold(checksums...)
+	}
+	if ctx.opts.TimePrecision > 0 {
+		t = t.Truncate(ctx.opts.TimePrecision)
+	}
+	checksums = append(checksums, ctx.scalarChecksum(reflect.Int64, intToBytes(t.UnixNano())))
+	if ctx.opts.IncludeLocation {
+		_, offset := t.Zone()
+		checksums = append(checksums, ctx.scalarChecksum(reflect.Int64, intToBytes(int64(offset))))
+	}
+	return ctx.fold(checksums...)
+}
+
+// durationChecksum calculates checksum of a time.Duration value, taking TimePrecision into account.
+// Without TimePrecision, a duration has the same checksum as an integer of its nanoseconds.
+func (ctx *checksumContext) durationChecksum(d time.Duration) []byte {
+	if ctx.opts.TimePrecision > 0 {
+		d = d.Truncate(ctx.opts.TimePrecision)
+	}
+	return ctx.intChecksum(reflect.Int64, int64(d))
+}
@@ -0,0 +1,145 @@
+package checksum
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+type MyTime time.Time
+
+type MyStructWithTimes struct {
+	CreatedAt MyTime
+	Timeout   time.Duration
+	updatedAt *time.Time
+}
+
+func TestChecksum_NamedTime(t *testing.T) {
+	testName := "TestChecksum_NamedTime"
+	now := time.Now()
+	myNow := MyTime(now)
+	later := now.Add(time.Nanosecond)
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, now))
+			checksum2 := fmt.Sprintf("%x", Checksum(hf, myNow))
+			checksum3 := fmt.Sprintf("%x", Checksum(hf, &myNow))
+			checksum4 := fmt.Sprintf("%x", Checksum(hf, MyTime(now.In(time.UTC).Round(0))))
+			if checksum1 != checksum2 || checksum1 != checksum3 || checksum1 != checksum4 {
+				t.Fatalf("%s failed: checksums of %#v must be the same, received %s, %s, %s and %s", testName+"/"+name, now, checksum1, checksum2, checksum3, checksum4)
+			}
+			if checksum := fmt.Sprintf("%x", Checksum(hf, MyTime(later))); checksum == checksum1 {
+				t.Fatalf("%s failed: Checksum(%#v)=%s must NOT be the same as Checksum(%#v)=%s", testName+"/"+name, later, checksum, now, checksum1)
+			}
+		})
+	}
+}
+
+func TestChecksum_TimePointersInMaps(t *testing.T) {
+	testName := "TestChecksum_TimePointersInMaps"
+	now := time.Now()
+	myNow := MyTime(now)
+	v1 := map[string]time.Time{"t": now}
+	v2 := map[string]*time.Time{"t": &now}
+	v3 := map[string]interface{}{"t": &myNow}
+	v4 := MyStructWithTimes{CreatedAt: myNow, Timeout: time.Second, updatedAt: &now}
+	v5 := map[string]interface{}{"CreatedAt": now, "Timeout": time.Second, "updatedAt": now}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksum1 := fmt.Sprintf("%x", Checksum(hf, v1))
+			for _, v := range []interface{}{v2, v3} {
+				if checksum := fmt.Sprintf("%x", Checksum(hf, v)); checksum != checksum1 {
+					t.Fatalf("%s failed: Checksum(%#v)=%s must be the same as Checksum(%#v)=%s", testName+"/"+name, v, checksum, v1, checksum1)
+				}
+			}
+			if checksum4, checksum5 := fmt.Sprintf("%x", Checksum(hf, v4)), fmt.Sprintf("%x", Checksum(hf, v5)); checksum4 == checksum5 {
+				t.Fatalf("%s failed: checksum of struct %#v must NOT be the same as checksum of map %#v", testName+"/"+name, v4, v5)
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_TimePrecision(t *testing.T) {
+	testName := "TestChecksumWithOptions_TimePrecision"
+	t1 := time.Date(2021, 2, 3, 4, 5, 6, 123456789, time.UTC)
+	t2 := time.Date(2021, 2, 3, 4, 5, 6, 123999999, time.UTC)
+	t3 := time.Date(2021, 2, 3, 4, 5, 6, 124000000, time.UTC)
+	testCases := []struct {
+		name      string
+		opts      Options
+		same      []interface{}
+		different []interface{}
+	}{
+		{name: "none", opts: Options{}, same: []interface{}{t1, MyTime(t1)}, different: []interface{}{t2, t3}},
+		{name: "millisecond", opts: Options{TimePrecision: time.Millisecond}, same: []interface{}{t1, t2, MyTime(t2), t1.Truncate(time.Millisecond)}, different: []interface{}{t3}},
+		{name: "second", opts: Options{TimePrecision: time.Second}, same: []interface{}{t1, t2, t3, t1.Truncate(time.Second)}, different: []interface{}{t1.Add(time.Second)}},
+		{name: "duration_none", opts: Options{}, same: []interface{}{1500 * time.Millisecond, int64(1500000000)}, different: []interface{}{time.Second}},
+		{name: "duration_second", opts: Options{TimePrecision: time.Second}, same: []interface{}{1500 * time.Millisecond, time.Second, 1999 * time.Millisecond}, different: []interface{}{2 * time.Second, int64(1500000000)}},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, testCase := range testCases {
+				checksum0 := fmt.Sprintf("%x", ChecksumWithOptions(hf, testCase.same[0], testCase.opts))
+				for _, v := range testCase.same[1:] {
+					if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, testCase.opts)); checksum != checksum0 {
+						t.Fatalf("%s failed: checksum of %#v=%s must be the same as checksum of %#v=%s", testName+"/"+name+"/"+testCase.name, v, checksum, testCase.same[0], checksum0)
+					}
+				}
+				for _, v := range testCase.different {
+					if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, testCase.opts)); checksum == checksum0 {
+						t.Fatalf("%s failed: checksum of %#v=%s must NOT be the same as checksum of %#v=%s", testName+"/"+name+"/"+testCase.name, v, checksum, testCase.same[0], checksum0)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_IncludeLocation(t *testing.T) {
+	testName := "TestChecksumWithOptions_IncludeLocation"
+	utc := time.Date(2021, 2, 3, 4, 5, 6, 0, time.UTC)
+	plus7 := utc.In(time.FixedZone("ICT", 7*3600))
+	plus7Other := utc.In(time.FixedZone("OTHER", 7*3600))
+	opts := Options{IncludeLocation: true}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			if fmt.Sprintf("%x", Checksum(hf, utc)) != fmt.Sprintf("%x", Checksum(hf, plus7)) {
+				t.Fatalf("%s failed: by default, location must not affect checksum", testName+"/"+name)
+			}
+			checksumUtc := fmt.Sprintf("%x", ChecksumWithOptions(hf, utc, opts))
+			checksumPlus7 := fmt.Sprintf("%x", ChecksumWithOptions(hf, plus7, opts))
+			checksumPlus7Other := fmt.Sprintf("%x", ChecksumWithOptions(hf, plus7Other, opts))
+			if checksumUtc == checksumPlus7 {
+				t.Fatalf("%s failed: checksum of %s must NOT be the same as checksum of %s", testName+"/"+name, utc, plus7)
+			}
+			if checksumPlus7 != checksumPlus7Other {
+				t.Fatalf("%s failed: checksum of %s must be the same as checksum of %s", testName+"/"+name, plus7, plus7Other)
+			}
+		})
+	}
+}
+
+func TestChecksumWithOptions_DistinguishZeroTime(t *testing.T) {
+	testName := "TestChecksumWithOptions_DistinguishZeroTime"
+	opts := Options{DistinguishZeroTime: true, TimePrecision: time.Second}
+	zero := time.Time{}
+	vList := []interface{}{nil, time.Unix(0, 0), time.Unix(0, 0).UTC(), zero.Add(time.Nanosecond), zero.Add(time.Second), int64(0)}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			checksumZero := fmt.Sprintf("%x", ChecksumWithOptions(hf, zero, opts))
+			if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, MyTime(zero), opts)); checksum != checksumZero {
+				t.Fatalf("%s failed: checksum of named zero time %s must be the same as checksum of zero time %s", testName+"/"+name, checksum, checksumZero)
+			}
+			for _, v := range vList {
+				if checksum := fmt.Sprintf("%x", ChecksumWithOptions(hf, v, opts)); checksum == checksumZero {
+					t.Fatalf("%s failed: checksum of %#v=%s must NOT be the same as checksum of zero time", testName+"/"+name, v, checksum)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package checksum
+
+import (
+	"bytes"
+	"sort"
+)
+
+// TreeNode is a node of a Merkle tree of checksums, built by Tree.
+//
+// Each node holds the checksum of a value; its children hold the checksums of the value's map entries,
+// struct fields or slice/array elements. Checksum of the root node is the same as Checksum of the whole value.
+type TreeNode struct {
+	// Key identifies the node among its siblings: a map key, a struct field name or a slice index such as "[1]".
+	Key string
+	// Path is the semita-style path of the node from the root, e.g. "Employees[1].email". Path of the root node is "".
+	Path     string
+	Checksum []byte
+	// Children are in order of slice indexes, struct fields or sorted map keys. Scalar values have no children.
+	Children []*TreeNode
+}
+
+// sortTreeNodes sorts nodes by their keys.
+func sortTreeNodes(nodes []*TreeNode) {
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].Key < nodes[j].Key })
+}
+
+// Tree calculates checksum of an input using the provided hash function, and returns the result as a Merkle tree
+// with one node per map entry, struct field and slice/array element.
+//
+// Tree(hf, v).Checksum is the same as Checksum(hf, v).
+func Tree(hf HashFunc, v interface{}) *TreeNode {
+	return TreeWithOptions(hf, v, Options{})
+}
+
+// TreeWithOptions is similar to Tree, but uses the provided options to calculate checksums.
+func TreeWithOptions(hf HashFunc, v interface{}, opts Options) *TreeNode {
+	root := &TreeNode{}
+	ctx := newChecksumContext(hf, opts)
+	ctx.trackPath = true
+	ctx.node = root
+	root.Checksum = checksumRoot(ctx, v)
+	return root
+}
+
+// Diff compares two checksum trees and returns the paths whose checksums differ, e.g. []string{"Employees[1].email"}.
+//
+// Only the deepest differing paths are returned: if a node has different checksums in a and b, its children are
+// compared; paths that exist in only one of the trees are returned as well. Nil is returned if the two trees have
+// the same checksum.
+func Diff(a, b *TreeNode) []string {
+	var result []string
+	diffTree(a, b, &result)
+	return result
+}
+
+func diffTree(a, b *TreeNode, result *[]string) {
+	if a == nil || b == nil {
+		if a != nil {
+			*result = append(*result, a.Path)
+		} else if b != nil {
+			*result = append(*result, b.Path)
+		}
+		return
+	}
+	if bytes.Equal(a.Checksum, b.Checksum) {
+		return
+	}
+	if len(a.Children) == 0 || len(b.Children) == 0 {
+		*result = append(*result, a.Path)
+		return
+	}
+	n := len(*result)
+	bChildren := make(map[string]*TreeNode, len(b.Children))
+	for _, child := range b.Children {
+		bChildren[child.Key] = child
+	}
+	for _, child := range a.Children {
+		diffTree(child, bChildren[child.Key], result)
+		delete(bChildren, child.Key)
+	}
+	for _, child := range b.Children {
+		if _, ok := bChildren[child.Key]; ok {
+			*result = append(*result, child.Path)
+		}
+	}
+	if len(*result) == n {
+		// children are the same but the nodes are not, e.g. values of different types with the same content
+		*result = append(*result, a.Path)
+	}
+}
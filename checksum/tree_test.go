@@ -0,0 +1,150 @@
+package checksum
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTree_Checksum(t *testing.T) {
+	testName := "TestTree_Checksum"
+	now := time.Now()
+	vList := []interface{}{
+		nil, 1, "a string", now, []int{1, 2, 3}, map[string]interface{}{"a": 1, "b": []string{"x", "y"}},
+		MyStructPubPriv{S: "string", N: 1, F: 2.3, s: "a string", n: 2, f: 3.4},
+		&MyStructAllPublic{S: "string", A: []interface{}{1, now}, M: map[string]interface{}{"t": &now}},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, v := range vList {
+				tree := Tree(hf, v)
+				if expected := Checksum(hf, v); !reflect.DeepEqual(tree.Checksum, expected) {
+					t.Fatalf("%s failed: expected %x but received %x", testName+"/"+name, expected, tree.Checksum)
+				}
+				if tree.Path != "" {
+					t.Fatalf("%s failed: expected root path to be empty but received %#v", testName+"/"+name, tree.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestTree_Nodes(t *testing.T) {
+	testName := "TestTree_Nodes"
+	v := map[string]interface{}{
+		"Name": "Monster Corp.",
+		"Employees": []interface{}{
+			map[string]interface{}{"name": "Mr. Monster", "email": "monster@domain.com"},
+			MyStruct1{S: "a string"},
+		},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			tree := Tree(hf, v)
+			var paths []string
+			var walk func(node *TreeNode)
+			walk = func(node *TreeNode) {
+				paths = append(paths, node.Path)
+				if expected := Checksum(hf, nodeValue(v, node.Path)); !reflect.DeepEqual(node.Checksum, expected) {
+					t.Fatalf("%s failed: path %s - expected %x but received %x", testName+"/"+name, node.Path, expected, node.Checksum)
+				}
+				for _, child := range node.Children {
+					walk(child)
+				}
+			}
+			walk(tree)
+			expected := []string{"", "Employees", "Employees[0]", "Employees[0].email", "Employees[0].name", "Employees[1]", "Employees[1].S", "Name"}
+			if !reflect.DeepEqual(paths, expected) {
+				t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+name, expected, paths)
+			}
+		})
+	}
+}
+
+// nodeValue returns the value at a path of the test data in TestTree_Nodes.
+func nodeValue(v map[string]interface{}, path string) interface{} {
+	employees := v["Employees"].([]interface{})
+	switch path {
+	case "":
+		return v
+	case "Name":
+		return v["Name"]
+	case "Employees":
+		return employees
+	case "Employees[0]":
+		return employees[0]
+	case "Employees[0].email", "Employees[0].name":
+		return employees[0].(map[string]interface{})[path[len("Employees[0]."):]]
+	case "Employees[1]":
+		return employees[1]
+	case "Employees[1].S":
+		return employees[1].(MyStruct1).S
+	}
+	panic(fmt.Sprintf("unknown path %s", path))
+}
+
+func TestDiff(t *testing.T) {
+	testName := "TestDiff"
+	newDoc := func() map[string]interface{} {
+		return map[string]interface{}{
+			"Name": "Monster Corp.",
+			"Year": 2013,
+			"Employees": []map[string]interface{}{
+				{"name": "Mr. Monster", "email": "monster@domain.com", "options": map[string]interface{}{"work_hours": []int{9, 10, 11}}},
+				{"name": "Mrs. Monster", "email": "mrs.monster@domain.com"},
+			},
+		}
+	}
+	testData := []struct {
+		update   func(doc map[string]interface{})
+		expected []string
+	}{
+		{func(doc map[string]interface{}) {}, nil},
+		{func(doc map[string]interface{}) { doc["Year"] = 2014 }, []string{"Year"}},
+		{func(doc map[string]interface{}) {
+			doc["Employees"].([]map[string]interface{})[1]["email"] = "mrs@domain.com"
+		}, []string{"Employees[1].email"}},
+		{func(doc map[string]interface{}) {
+			doc["Employees"].([]map[string]interface{})[0]["options"].(map[string]interface{})["work_hours"].([]int)[2] = 12
+			doc["Name"] = "Monster Inc."
+		}, []string{"Employees[0].options.work_hours[2]", "Name"}},
+		{func(doc map[string]interface{}) { delete(doc, "Year"); doc["Founded"] = 2013 }, []string{"Year", "Founded"}},
+		{func(doc map[string]interface{}) {
+			doc["Employees"] = doc["Employees"].([]map[string]interface{})[:1]
+		}, []string{"Employees[1]"}},
+	}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			for _, td := range testData {
+				doc1, doc2 := newDoc(), newDoc()
+				td.update(doc2)
+				diff := Diff(Tree(hf, doc1), Tree(hf, doc2))
+				if !reflect.DeepEqual(diff, td.expected) {
+					t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+name, td.expected, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiff_TypeChange(t *testing.T) {
+	testName := "TestDiff_TypeChange"
+	v1 := map[string]interface{}{"a": MyStruct1{S: "a string"}, "b": 1}
+	v2 := map[string]interface{}{"a": MyStruct2{S: "a string"}, "b": 1}
+	for i, name := range nameList {
+		t.Run(name, func(t *testing.T) {
+			hf := hfList[i]
+			diff := Diff(Tree(hf, v1), Tree(hf, v2))
+			if expected := []string{"a"}; !reflect.DeepEqual(diff, expected) {
+				t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+name, expected, diff)
+			}
+			if diff := Diff(nil, Tree(hf, v2)); !reflect.DeepEqual(diff, []string{""}) {
+				t.Fatalf("%s failed: expected %#v but received %#v", testName+"/"+name, []string{""}, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package checksum
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// pure-Go implementation of xxHash64 (https://github.com/Cyan4973/xxHash/blob/dev/doc/xxhash_spec.md), seed 0.
+
+// primes are vars, not consts, so that arithmetic on them wraps around instead of overflowing at compile time
+var (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+type xxHash64 struct {
+	v1, v2, v3, v4 uint64
+	total          uint64
+	mem            [32]byte
+	n              int // number of bytes buffered in mem
+}
+
+// NewXxHash64 returns a new hash.Hash64 computing the xxHash64 checksum (seed 0).
+// Sum appends the checksum in big-endian order.
+func NewXxHash64() hash.Hash64 {
+	h := &xxHash64{}
+	h.Reset()
+	return h
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMergeRound(acc, val uint64) uint64 {
+	acc ^= xxRound(0, val)
+	return acc*xxPrime1 + xxPrime4
+}
+
+// Reset implements hash.Hash.
+func (h *xxHash64) Reset() {
+	h.v1 = xxPrime1 + xxPrime2
+	h.v2 = xxPrime2
+	h.v3 = 0
+	h.v4 = -xxPrime1
+	h.total = 0
+	h.n = 0
+}
+
+// Size implements hash.Hash.
+func (h *xxHash64) Size() int { return 8 }
+
+// BlockSize implements hash.Hash.
+func (h *xxHash64) BlockSize() int { return 32 }
+
+// Write implements io.Writer.
+func (h *xxHash64) Write(p []byte) (int, error) {
+	n := len(p)
+	h.total += uint64(n)
+	if h.n+len(p) < 32 {
+		h.n += copy(h.mem[h.n:], p)
+		return n, nil
+	}
+	if h.n > 0 {
+		c := copy(h.mem[h.n:], p)
+		h.consume(h.mem[:])
+		p = p[c:]
+		h.n = 0
+	}
+	for ; len(p) >= 32; p = p[32:] {
+		h.consume(p[:32])
+	}
+	h.n = copy(h.mem[:], p)
+	return n, nil
+}
+
+// consume processes a 32-byte stripe.
+func (h *xxHash64) consume(b []byte) {
+	h.v1 = xxRound(h.v1, binary.LittleEndian.Uint64(b[0:8]))
+	h.v2 = xxRound(h.v2, binary.LittleEndian.Uint64(b[8:16]))
+	h.v3 = xxRound(h.v3, binary.LittleEndian.Uint64(b[16:24]))
+	h.v4 = xxRound(h.v4, binary.LittleEndian.Uint64(b[24:32]))
+}
+
+// Sum64 implements hash.Hash64.
+func (h *xxHash64) Sum64() uint64 {
+	var result uint64
+	if h.total >= 32 {
+		result = bits.RotateLeft64(h.v1, 1) + bits.RotateLeft64(h.v2, 7) + bits.RotateLeft64(h.v3, 12) + bits.RotateLeft64(h.v4, 18)
+		result = xxMergeRound(result, h.v1)
+		result = xxMergeRound(result, h.v2)
+		result = xxMergeRound(result, h.v3)
+		result = xxMergeRound(result, h.v4)
+	} else {
+		result = xxPrime5
+	}
+	result += h.total
+
+	p := h.mem[:h.n]
+	for ; len(p) >= 8; p = p[8:] {
+		result ^= xxRound(0, binary.LittleEndian.Uint64(p))
+		result = bits.RotateLeft64(result, 27)*xxPrime1 + xxPrime4
+	}
+	if len(p) >= 4 {
+		result ^= uint64(binary.LittleEndian.Uint32(p)) * xxPrime1
+		result = bits.RotateLeft64(result, 23)*xxPrime2 + xxPrime3
+		p = p[4:]
+	}
+	for _, b := range p {
+		result ^= uint64(b) * xxPrime5
+		result = bits.RotateLeft64(result, 11) * xxPrime1
+	}
+
+	result ^= result >> 33
+	result *= xxPrime2
+	result ^= result >> 29
+	result *= xxPrime3
+	result ^= result >> 32
+	return result
+}
+
+// Sum implements hash.Hash.
+func (h *xxHash64) Sum(b []byte) []byte {
+	s := h.Sum64()
+	return append(b, byte(s>>56), byte(s>>48), byte(s>>40), byte(s>>32), byte(s>>24), byte(s>>16), byte(s>>8), byte(s))
+}
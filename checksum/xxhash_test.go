@@ -0,0 +1,55 @@
+package checksum
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestXxHash64(t *testing.T) {
+	testName := "TestXxHash64"
+	testData := []struct {
+		input    string
+		expected uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"xxHash64 - the quick brown fox jumps over the lazy dog", 0x3c7a4144a7c2c503},
+	}
+	for _, td := range testData {
+		h := NewXxHash64()
+		h.Write([]byte(td.input))
+		if h.Sum64() != td.expected {
+			t.Fatalf("%s failed: {input: %#v / expected: %x / received: %x}", testName, td.input, td.expected, h.Sum64())
+		}
+		if sum := fmt.Sprintf("%x", h.Sum(nil)); sum != fmt.Sprintf("%016x", td.expected) {
+			t.Fatalf("%s failed: {input: %#v / expected: %016x / received: %s}", testName, td.input, td.expected, sum)
+		}
+	}
+}
+
+func TestXxHash64_Streaming(t *testing.T) {
+	testName := "TestXxHash64_Streaming"
+	data := bytes.Repeat([]byte("0123456789abcdefghijklmnopqrstuvwxyz"), 10)
+	h := NewXxHash64()
+	h.Write(data)
+	expected := h.Sum64()
+	for _, chunkSize := range []int{1, 3, 7, 31, 32, 33, 64, 100} {
+		h.Reset()
+		for p := data; len(p) > 0; {
+			n := chunkSize
+			if n > len(p) {
+				n = len(p)
+			}
+			h.Write(p[:n])
+			p = p[n:]
+		}
+		if h.Sum64() != expected {
+			t.Fatalf("%s failed: {chunk size: %d / expected: %x / received: %x}", testName, chunkSize, expected, h.Sum64())
+		}
+	}
+	if h.Size() != 8 || h.BlockSize() != 32 {
+		t.Fatalf("%s failed: {size: %d / block size: %d}", testName, h.Size(), h.BlockSize())
+	}
+}
@@ -0,0 +1,417 @@
+package g18
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// CachePolicy specifies which entry a Cache evicts when it is full.
+//
+// @Available since <<VERSION>>
+type CachePolicy int
+
+const (
+	// CacheLRU evicts the least recently used entry.
+	CacheLRU CachePolicy = iota
+
+	// CacheLFU evicts the least frequently used entry; ties are broken by evicting the least recently used one.
+	CacheLFU
+
+	// CacheTTL expires entries after CacheOptions.TTL since they were set, and evicts the oldest entry when full.
+	CacheTTL
+)
+
+// EvictionReason tells why an entry was removed from a Cache.
+//
+// @Available since <<VERSION>>
+type EvictionReason int
+
+const (
+	// EvictionCapacity means the entry was evicted to make room for a new one.
+	EvictionCapacity EvictionReason = iota
+
+	// EvictionExpired means the entry was removed because it expired.
+	EvictionExpired
+
+	// EvictionRemoved means the entry was removed by Cache.Delete or Cache.Clear.
+	EvictionRemoved
+)
+
+// CacheOptions defines the behavior of a Cache.
+//
+// @Available since <<VERSION>>
+type CacheOptions[K comparable, V any] struct {
+	// Policy is the eviction policy, default CacheLRU.
+	Policy CachePolicy
+
+	// MaxSize is the maximum number of entries; 0 (or negative) means unbounded.
+	MaxSize int
+
+	// TTL is the time-to-live of entries, counted from when they were set; 0 means entries never expire.
+	// TTL must be positive for policy CacheTTL, and can also be used with CacheLRU and CacheLFU.
+	TTL time.Duration
+
+	// OnEvict, if not nil, is called after an entry has been removed from the cache, outside of the cache's lock
+	// (hence it may call the cache's methods).
+	OnEvict func(key K, value V, reason EvictionReason)
+
+	// Now, if not nil, is used instead of time.Now to read the current time, e.g. so that tests can control expiry.
+	Now func() time.Time
+}
+
+var errCacheLoaderPanicked = errors.New("cache loader panicked")
+
+// CacheStats holds the hit/miss statistics of a Cache.
+//
+// @Available since <<VERSION>>
+type CacheStats struct {
+	Hits      uint64 // number of lookups that found an entry
+	Misses    uint64 // number of lookups that did not find an entry (or found an expired one)
+	Evictions uint64 // number of entries evicted because of capacity or expiry
+}
+
+// HitRatio returns the ratio of hits over lookups, or 0 if there has been no lookup.
+func (s CacheStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// cacheEntry is an entry of a Cache, linked in the recency list (LRU and TTL) or in the list of its frequency (LFU).
+type cacheEntry[K comparable, V any] struct {
+	key      K
+	value    V
+	expireAt time.Time
+	freq     int
+	node     *listNode[*cacheEntry[K, V]]
+}
+
+// cacheLoad is an in-flight load of GetOrLoad.
+type cacheLoad[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// evicted is an entry that has been removed and is waiting for OnEvict to be called.
+type evicted[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// Cache is a generic in-memory cache with a maximum size and LRU, LFU or TTL eviction. All operations are O(1), except
+// that LFU eviction may scan the distinct frequencies of entries after deletions.
+//
+// A Cache is safe for concurrent use.
+//
+// @Available since <<VERSION>>
+type Cache[K comparable, V any] struct {
+	opts    CacheOptions[K, V]
+	lock    sync.Mutex
+	entries map[K]*cacheEntry[K, V]
+	order   linkedList[*cacheEntry[K, V]]          // LRU and TTL: front is the next entry to evict
+	freqs   map[int]*linkedList[*cacheEntry[K, V]] // LFU: entries by frequency, in each list front is the least recently used
+	minFreq int                                    // LFU: lowest frequency, may be stale after deletions
+	loads   map[K]*cacheLoad[V]
+	stats   CacheStats
+}
+
+// NewCache creates a new Cache with the provided options. This function panics if the policy is CacheTTL but TTL is not positive.
+//
+// @Available since <<VERSION>>
+func NewCache[K comparable, V any](opts CacheOptions[K, V]) *Cache[K, V] {
+	if opts.Policy == CacheTTL && opts.TTL <= 0 {
+		panic("TTL must be positive for policy CacheTTL")
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Cache[K, V]{
+		opts:    opts,
+		entries: make(map[K]*cacheEntry[K, V]),
+		freqs:   make(map[int]*linkedList[*cacheEntry[K, V]]),
+		loads:   make(map[K]*cacheLoad[V]),
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries that have not been removed yet (see Purge).
+func (c *Cache[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.entries)
+}
+
+// Stats returns the hit/miss statistics of the cache.
+func (c *Cache[K, V]) Stats() CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.stats
+}
+
+// Get returns the value associated with a key. The second return value is false if the key does not exist or has expired.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.lock.Lock()
+	value, ok, evictedList := c.get(key)
+	c.lock.Unlock()
+	c.notify(evictedList)
+	return value, ok
+}
+
+// Set associates a value with a key, evicting an entry if the cache is full.
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.lock.Lock()
+	evictedList := c.set(key, value)
+	c.lock.Unlock()
+	c.notify(evictedList)
+}
+
+// Delete removes a key from the cache. The return value is false if the key does not exist.
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.lock.Lock()
+	e, ok := c.entries[key]
+	if ok {
+		c.remove(e)
+	}
+	c.lock.Unlock()
+	if ok {
+		c.notify([]evicted[K, V]{{e.key, e.value, EvictionRemoved}})
+	}
+	return ok
+}
+
+// Clear removes all entries from the cache. Statistics are kept.
+func (c *Cache[K, V]) Clear() {
+	c.lock.Lock()
+	var evictedList []evicted[K, V]
+	if c.opts.OnEvict != nil {
+		evictedList = make([]evicted[K, V], 0, len(c.entries))
+		for _, e := range c.entries {
+			evictedList = append(evictedList, evicted[K, V]{e.key, e.value, EvictionRemoved})
+		}
+	}
+	c.entries = make(map[K]*cacheEntry[K, V])
+	c.order.clear()
+	c.freqs = make(map[int]*linkedList[*cacheEntry[K, V]])
+	c.minFreq = 0
+	c.lock.Unlock()
+	c.notify(evictedList)
+}
+
+// Purge removes all expired entries from the cache and returns the number of removed entries.
+// Expired entries are otherwise removed lazily, when they are looked up or evicted.
+func (c *Cache[K, V]) Purge() int {
+	if c.opts.TTL <= 0 {
+		return 0
+	}
+	c.lock.Lock()
+	now := c.opts.Now()
+	var evictedList []evicted[K, V]
+	for _, e := range c.entries {
+		if !now.Before(e.expireAt) {
+			c.remove(e)
+			c.stats.Evictions++
+			evictedList = append(evictedList, evicted[K, V]{e.key, e.value, EvictionExpired})
+		}
+	}
+	c.lock.Unlock()
+	c.notify(evictedList)
+	return len(evictedList)
+}
+
+// GetOrLoad returns the value associated with a key. If the key does not exist (or has expired), loader is called to
+// load the value, which is then stored in the cache. If loader returns an error, nothing is stored and the error is returned.
+//
+// Concurrent calls of GetOrLoad for the same missing key call loader only once; the other callers wait for and share its result.
+// If loader panics, the panic propagates to its caller, and the waiting callers receive an error.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func(key K) (V, error)) (V, error) {
+	c.lock.Lock()
+	value, ok, evictedList := c.get(key)
+	if ok {
+		c.lock.Unlock()
+		c.notify(evictedList)
+		return value, nil
+	}
+	if load, ok := c.loads[key]; ok {
+		c.lock.Unlock()
+		c.notify(evictedList)
+		load.wg.Wait()
+		return load.value, load.err
+	}
+	load := &cacheLoad[V]{}
+	load.wg.Add(1)
+	c.loads[key] = load
+	c.lock.Unlock()
+	c.notify(evictedList)
+	evictedList = nil
+
+	completed := false
+	defer func() {
+		if !completed {
+			// loader panicked: release the waiting callers without storing anything
+			c.lock.Lock()
+			delete(c.loads, key)
+			c.lock.Unlock()
+			load.err = errCacheLoaderPanicked
+			load.wg.Done()
+		}
+	}()
+	value, err := loader(key)
+	completed = true
+
+	c.lock.Lock()
+	delete(c.loads, key)
+	if err == nil {
+		evictedList = c.set(key, value)
+	}
+	c.lock.Unlock()
+	load.value, load.err = value, err
+	load.wg.Done()
+	c.notify(evictedList)
+	return value, err
+}
+
+// notify calls OnEvict for the removed entries. It must be called without holding the lock.
+func (c *Cache[K, V]) notify(evictedList []evicted[K, V]) {
+	if c.opts.OnEvict == nil {
+		return
+	}
+	for _, e := range evictedList {
+		c.opts.OnEvict(e.key, e.value, e.reason)
+	}
+}
+
+// expired checks if an entry has expired.
+func (c *Cache[K, V]) expired(e *cacheEntry[K, V]) bool {
+	return c.opts.TTL > 0 && !c.opts.Now().Before(e.expireAt)
+}
+
+// get looks up a key, updating statistics and the eviction order. It must be called while holding the lock.
+func (c *Cache[K, V]) get(key K) (V, bool, []evicted[K, V]) {
+	var zero V
+	e, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return zero, false, nil
+	}
+	if c.expired(e) {
+		c.remove(e)
+		c.stats.Misses++
+		c.stats.Evictions++
+		return zero, false, []evicted[K, V]{{e.key, e.value, EvictionExpired}}
+	}
+	c.stats.Hits++
+	c.touch(e)
+	return e.value, true, nil
+}
+
+// set stores a key/value, evicting an entry if the cache is full. It must be called while holding the lock.
+func (c *Cache[K, V]) set(key K, value V) []evicted[K, V] {
+	var expireAt time.Time
+	if c.opts.TTL > 0 {
+		expireAt = c.opts.Now().Add(c.opts.TTL)
+	}
+	if e, ok := c.entries[key]; ok {
+		e.value, e.expireAt = value, expireAt
+		if c.opts.Policy == CacheTTL {
+			// expiry has been renewed: the entry becomes the youngest one
+			c.order.moveToBack(e.node)
+		} else {
+			c.touch(e)
+		}
+		return nil
+	}
+
+	var evictedList []evicted[K, V]
+	if c.opts.MaxSize > 0 && len(c.entries) >= c.opts.MaxSize {
+		if victim := c.victim(); victim != nil {
+			reason := EvictionCapacity
+			if c.expired(victim) {
+				reason = EvictionExpired
+			}
+			c.remove(victim)
+			c.stats.Evictions++
+			evictedList = []evicted[K, V]{{victim.key, victim.value, reason}}
+		}
+	}
+
+	e := &cacheEntry[K, V]{key: key, value: value, expireAt: expireAt}
+	c.entries[key] = e
+	if c.opts.Policy == CacheLFU {
+		e.freq = 1
+		c.minFreq = 1
+		e.node = c.freqList(1).pushBack(e)
+	} else {
+		e.node = c.order.pushBack(e)
+	}
+	return evictedList
+}
+
+// touch records an access to an entry. It must be called while holding the lock.
+func (c *Cache[K, V]) touch(e *cacheEntry[K, V]) {
+	switch c.opts.Policy {
+	case CacheLRU:
+		c.order.moveToBack(e.node)
+	case CacheLFU:
+		c.unlinkFreq(e)
+		e.freq++
+		e.node = c.freqList(e.freq).pushBack(e)
+	}
+}
+
+// victim returns the entry to evict when the cache is full. It must be called while holding the lock.
+func (c *Cache[K, V]) victim() *cacheEntry[K, V] {
+	if c.opts.Policy != CacheLFU {
+		if n := c.order.front(); n != nil {
+			return n.value
+		}
+		return nil
+	}
+	if _, ok := c.freqs[c.minFreq]; !ok {
+		// minFreq is stale after deletions: find the actual lowest frequency
+		c.minFreq = 0
+		for freq := range c.freqs {
+			if c.minFreq == 0 || freq < c.minFreq {
+				c.minFreq = freq
+			}
+		}
+	}
+	if l, ok := c.freqs[c.minFreq]; ok {
+		return l.front().value
+	}
+	return nil
+}
+
+// remove removes an entry from the cache. It must be called while holding the lock.
+func (c *Cache[K, V]) remove(e *cacheEntry[K, V]) {
+	delete(c.entries, e.key)
+	if c.opts.Policy == CacheLFU {
+		c.unlinkFreq(e)
+	} else {
+		c.order.remove(e.node)
+	}
+}
+
+// freqList returns the list of entries of a frequency, creating it if needed.
+func (c *Cache[K, V]) freqList(freq int) *linkedList[*cacheEntry[K, V]] {
+	l, ok := c.freqs[freq]
+	if !ok {
+		l = &linkedList[*cacheEntry[K, V]]{}
+		c.freqs[freq] = l
+	}
+	return l
+}
+
+// unlinkFreq removes an entry from the list of its frequency, dropping the list if it becomes empty.
+func (c *Cache[K, V]) unlinkFreq(e *cacheEntry[K, V]) {
+	l := c.freqs[e.freq]
+	l.remove(e.node)
+	if l.len == 0 {
+		delete(c.freqs, e.freq)
+		if c.minFreq == e.freq {
+			c.minFreq++
+		}
+	}
+}
@@ -0,0 +1,434 @@
+package g18
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock, injected into caches via CacheOptions.Now.
+type fakeClock struct {
+	lock sync.Mutex
+	now  time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+}
+
+type evictionRecord struct {
+	key    string
+	value  int
+	reason EvictionReason
+}
+
+// evictionRecorder records evictions of a cache via CacheOptions.OnEvict.
+type evictionRecorder struct {
+	lock    sync.Mutex
+	records []evictionRecord
+}
+
+func (r *evictionRecorder) OnEvict(key string, value int, reason EvictionReason) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.records = append(r.records, evictionRecord{key, value, reason})
+}
+
+func (r *evictionRecorder) Records() []evictionRecord {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	result := r.records
+	r.records = nil
+	return result
+}
+
+func cacheKeys[V any](c *Cache[string, V]) []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	result := make([]string, 0, len(c.entries))
+	for k := range c.entries {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestCache_LRU(t *testing.T) {
+	testName := "TestCache_LRU"
+	recorder := &evictionRecorder{}
+	c := NewCache(CacheOptions[string, int]{Policy: CacheLRU, MaxSize: 3, OnEvict: recorder.OnEvict})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")    // order (least recent first): b, c, a
+	c.Set("c", 4) // order: b, a, c
+	c.Set("d", 5) // evicts b
+	if v, expected := cacheKeys(c), []string{"a", "c", "d"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	c.Set("e", 6) // evicts a
+	if v, expected := recorder.Records(), []evictionRecord{{"b", 2, EvictionCapacity}, {"a", 1, EvictionCapacity}}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, ok := c.Get("c"); !ok || v != 4 {
+		t.Fatalf("%s failed: expected (%#v, %#v) but received (%#v, %#v)", testName, 4, true, v, ok)
+	}
+	if c.Len() != 3 {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, 3, c.Len())
+	}
+}
+
+func TestCache_LFU(t *testing.T) {
+	testName := "TestCache_LFU"
+	recorder := &evictionRecorder{}
+	c := NewCache(CacheOptions[string, int]{Policy: CacheLFU, MaxSize: 3, OnEvict: recorder.OnEvict})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Get("c")    // frequencies: a=3, b=2, c=2 (c used more recently than b)
+	c.Set("d", 4) // evicts b
+	c.Set("e", 5) // evicts d, the only entry with frequency 1
+	if v, expected := recorder.Records(), []evictionRecord{{"b", 2, EvictionCapacity}, {"d", 4, EvictionCapacity}}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := cacheKeys(c), []string{"a", "c", "e"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	// deleting entries makes minFreq stale, eviction must still pick the least frequently used entry
+	c.Delete("e")
+	c.Get("a")
+	c.Set("f", 6) // frequencies: a=4, c=2, f=1
+	c.Get("f")
+	c.Get("f")
+	c.Get("f")    // frequencies: a=4, c=2, f=4
+	c.Set("g", 7) // evicts c
+	if v, expected := cacheKeys(c), []string{"a", "f", "g"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	c.Delete("g")
+	c.Set("h", 8)
+	c.Set("i", 9) // evicts h, frequencies of a and f are 4
+	if v, expected := cacheKeys(c), []string{"a", "f", "i"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	testName := "TestCache_TTL"
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	recorder := &evictionRecorder{}
+	c := NewCache(CacheOptions[string, int]{Policy: CacheTTL, TTL: time.Minute, MaxSize: 3, OnEvict: recorder.OnEvict, Now: clock.Now})
+	c.Set("a", 1)
+	clock.Advance(30 * time.Second)
+	c.Set("b", 2)
+	c.Get("a") // access does not extend the TTL nor change the eviction order
+	clock.Advance(30 * time.Second)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("%s failed: entry must expire after TTL", testName)
+	}
+	if v, ok := c.Get("b"); !ok || v != 2 {
+		t.Fatalf("%s failed: expected (%#v, %#v) but received (%#v, %#v)", testName, 2, true, v, ok)
+	}
+	if v, expected := recorder.Records(), []evictionRecord{{"a", 1, EvictionExpired}}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	c.Set("c", 3)
+	c.Set("b", 20) // renews expiry of b, which becomes the youngest entry
+	c.Set("d", 4)
+	c.Set("e", 5) // evicts c, the oldest entry
+	if v, expected := recorder.Records(), []evictionRecord{{"c", 3, EvictionCapacity}}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	clock.Advance(time.Minute)
+	c.Set("f", 6) // evicts b, reported as expired
+	if v, expected := recorder.Records(), []evictionRecord{{"b", 20, EvictionExpired}}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v := c.Purge(); v != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, 2, v)
+	}
+	if v, expected := cacheKeys(c), []string{"f"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("%s failed: expected panic for policy CacheTTL without TTL", testName)
+		}
+	}()
+	NewCache(CacheOptions[string, int]{Policy: CacheTTL})
+}
+
+func TestCache_LRUWithTTL(t *testing.T) {
+	testName := "TestCache_LRUWithTTL"
+	clock := &fakeClock{now: time.Now()}
+	c := NewCache(CacheOptions[string, int]{TTL: time.Second, Now: clock.Now})
+	c.Set("a", 1)
+	clock.Advance(999 * time.Millisecond)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatalf("%s failed: entry must not expire before TTL", testName)
+	}
+	clock.Advance(time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatalf("%s failed: entry must expire after TTL", testName)
+	}
+	if NewCache(CacheOptions[string, int]{}).Purge() != 0 {
+		t.Fatalf("%s failed: nothing to purge without TTL", testName)
+	}
+}
+
+func TestCache_DeleteClear(t *testing.T) {
+	testName := "TestCache_DeleteClear"
+	for _, policy := range []CachePolicy{CacheLRU, CacheLFU, CacheTTL} {
+		recorder := &evictionRecorder{}
+		c := NewCache(CacheOptions[string, int]{Policy: policy, TTL: time.Hour, MaxSize: 2, OnEvict: recorder.OnEvict})
+		c.Set("a", 1)
+		c.Set("b", 2)
+		if !c.Delete("a") || c.Delete("a") {
+			t.Fatalf("%s failed: Delete must return true only for existing keys", testName)
+		}
+		if v, expected := recorder.Records(), []evictionRecord{{"a", 1, EvictionRemoved}}; !reflect.DeepEqual(v, expected) {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+		}
+		c.Clear()
+		if v, expected := recorder.Records(), []evictionRecord{{"b", 2, EvictionRemoved}}; !reflect.DeepEqual(v, expected) {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+		}
+		if c.Len() != 0 {
+			t.Fatalf("%s failed: cache must be empty after Clear", testName)
+		}
+		c.Set("c", 3)
+		c.Set("d", 4)
+		c.Set("e", 5)
+		if v, expected := cacheKeys(c), []string{"d", "e"}; !reflect.DeepEqual(v, expected) {
+			t.Fatalf("%s failed: policy %d expected %#v but received %#v", testName, policy, expected, v)
+		}
+	}
+}
+
+func TestCache_Stats(t *testing.T) {
+	testName := "TestCache_Stats"
+	c := NewCache(CacheOptions[string, int]{MaxSize: 1})
+	if v := c.Stats().HitRatio(); v != 0 {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, 0, v)
+	}
+	c.Set("a", 1)
+	c.Get("a")
+	c.Get("a")
+	c.Get("a")
+	c.Get("b")
+	c.Set("b", 2)
+	if v, expected := c.Stats(), (CacheStats{Hits: 3, Misses: 1, Evictions: 1}); v != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v := c.Stats().HitRatio(); v != 0.75 {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, 0.75, v)
+	}
+}
+
+func TestCache_OnEvictReentrant(t *testing.T) {
+	testName := "TestCache_OnEvictReentrant"
+	var c *Cache[string, int]
+	c = NewCache(CacheOptions[string, int]{MaxSize: 1, OnEvict: func(key string, value int, reason EvictionReason) {
+		// OnEvict is called outside of the lock, so it can use the cache
+		if key == "a" {
+			c.Set("evicted-"+key, value)
+		}
+	}})
+	c.Set("a", 1)
+	c.Set("b", 2) // evicts a, then OnEvict evicts b
+	if v, expected := cacheKeys(c), []string{"evicted-a"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	testName := "TestCache_GetOrLoad"
+	c := NewCache(CacheOptions[string, int]{})
+	loads := 0
+	loader := func(key string) (int, error) {
+		loads++
+		return strconv.Atoi(key)
+	}
+	for i := 0; i < 3; i++ {
+		if v, err := c.GetOrLoad("12", loader); err != nil || v != 12 {
+			t.Fatalf("%s failed: expected (%#v, nil) but received (%#v, %s)", testName, 12, v, err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("%s failed: expected %#v loads but received %#v", testName, 1, loads)
+	}
+
+	// errors are returned and not cached
+	if _, err := c.GetOrLoad("x", loader); err == nil {
+		t.Fatalf("%s failed: expected error", testName)
+	}
+	if _, ok := c.Get("x"); ok || loads != 2 {
+		t.Fatalf("%s failed: failed loads must not be cached", testName)
+	}
+	if v, expected := c.Stats(), (CacheStats{Hits: 2, Misses: 3}); v != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestCache_GetOrLoadConcurrent(t *testing.T) {
+	testName := "TestCache_GetOrLoadConcurrent"
+	c := NewCache(CacheOptions[string, int]{MaxSize: 10})
+	var loads int32
+	start := make(chan struct{})
+	loader := func(key string) (int, error) {
+		atomic.AddInt32(&loads, 1)
+		<-start // hold the load until all callers are waiting
+		return len(key), nil
+	}
+	var wg sync.WaitGroup
+	const n = 20
+	results := make([]int, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = c.GetOrLoad("key", loader)
+		}(i)
+	}
+	for {
+		c.lock.Lock()
+		waiting := c.stats.Misses
+		c.lock.Unlock()
+		if waiting == n {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(start)
+	wg.Wait()
+	if loads != 1 {
+		t.Fatalf("%s failed: expected %#v loads but received %#v", testName, 1, loads)
+	}
+	for _, v := range results {
+		if v != 3 {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, 3, v)
+		}
+	}
+}
+
+func TestCache_GetOrLoadPanic(t *testing.T) {
+	testName := "TestCache_GetOrLoadPanic"
+	c := NewCache(CacheOptions[string, int]{})
+	started, release, panicked := make(chan struct{}), make(chan struct{}), make(chan interface{})
+	go func() {
+		defer func() { panicked <- recover() }()
+		c.GetOrLoad("key", func(string) (int, error) {
+			close(started)
+			<-release // hold the load until all callers are waiting
+			panic("loader failed")
+		})
+	}()
+	<-started
+	const n = 5
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := c.GetOrLoad("key", func(string) (int, error) { return 1, nil })
+			errs <- err
+		}()
+	}
+	// a caller joins the load in flight under the same lock as it counts its miss
+	for {
+		c.lock.Lock()
+		misses := c.stats.Misses
+		c.lock.Unlock()
+		if misses == n+1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	if r := <-panicked; r == nil {
+		t.Fatalf("%s failed: the panic must propagate to the caller running the loader", testName)
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errs; !errors.Is(err, errCacheLoaderPanicked) {
+			t.Fatalf("%s failed: expected error %s but received %v", testName, errCacheLoaderPanicked, err)
+		}
+	}
+	if _, ok := c.Get("key"); ok {
+		t.Fatalf("%s failed: nothing must be cached for a panicked load", testName)
+	}
+	// the cache must still be usable for the key
+	if v, err := c.GetOrLoad("key", func(string) (int, error) { return 2, nil }); err != nil || v != 2 {
+		t.Fatalf("%s failed: expected (%#v, nil) but received (%#v, %s)", testName, 2, v, err)
+	}
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	testName := "TestCache_Concurrent"
+	for _, policy := range []CachePolicy{CacheLRU, CacheLFU, CacheTTL} {
+		c := NewCache(CacheOptions[int, int]{Policy: policy, MaxSize: 50, TTL: time.Hour})
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 500; j++ {
+					key := (i*31 + j) % 100
+					switch j % 4 {
+					case 0:
+						c.Set(key, key)
+					case 1:
+						if v, ok := c.Get(key); ok && v != key {
+							t.Errorf("%s failed: expected %#v but received %#v", testName, key, v)
+						}
+					case 2:
+						c.GetOrLoad(key, func(k int) (int, error) { return k, nil })
+					case 3:
+						c.Delete(key)
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+		if c.Len() > 50 {
+			t.Fatalf("%s failed: policy %d exceeded max size: %d", testName, policy, c.Len())
+		}
+	}
+}
+
+func BenchmarkCache_LRU(b *testing.B) {
+	c := NewCache(CacheOptions[int, int]{MaxSize: 1000})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, ok := c.Get(i % 2000); !ok {
+			c.Set(i%2000, i)
+		}
+	}
+}
+
+func BenchmarkCache_LFU(b *testing.B) {
+	c := NewCache(CacheOptions[int, int]{Policy: CacheLFU, MaxSize: 1000})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, ok := c.Get(i % 2000); !ok {
+			c.Set(i%2000, i)
+		}
+	}
+}
@@ -0,0 +1,392 @@
+package g18
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelMap applies fn to each element of the input using up to workers goroutines, and returns the results in input
+// order. workers <= 0 means runtime.NumCPU().
+//
+// The first error returned by fn cancels the context passed to the other calls, no new element is processed, and the
+// error is returned once running calls have completed. If ctx is cancelled before fn has been called for all elements,
+// ctx.Err() is returned; a cancellation after the last call has started does not fail a call whose fn calls all
+// succeed. If ctx is already cancelled when ParallelMap is called, ctx.Err() is returned, even for an empty input.
+//
+// @Available since <<VERSION>>
+func ParallelMap[T, R any](ctx context.Context, input []T, workers int, fn func(ctx context.Context, v T) (R, error)) ([]R, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	workers = Min(workers, len(input))
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	result := make([]R, len(input))
+	var next int64 = -1
+	var errOnce sync.Once
+	var firstErr error
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				// check for completion first, so that a cancellation after the last element has been claimed is not a failure
+				i := int(atomic.AddInt64(&next, 1))
+				if i >= len(input) {
+					return
+				}
+				if err := ctx.Err(); err != nil {
+					fail(err)
+					return
+				}
+				r, err := fn(ctx, input[i])
+				if err != nil {
+					fail(err)
+					return
+				}
+				result[i] = r
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// Group runs functions in goroutines with a limit on how many run at the same time, and collects the first error,
+// similar to golang.org/x/sync/errgroup with SetLimit.
+//
+// @Available since <<VERSION>>
+type Group struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	sem     chan struct{}
+	errOnce sync.Once
+	err     error
+}
+
+// NewGroup creates a new Group that runs at most limit functions at the same time (limit <= 0 means no limit).
+//
+// The returned context is cancelled when a function returns an error or when Wait returns, whichever occurs first.
+//
+// @Available since <<VERSION>>
+func NewGroup(ctx context.Context, limit int) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	g := &Group{ctx: ctx, cancel: cancel}
+	if limit > 0 {
+		g.sem = make(chan struct{}, limit)
+	}
+	return g, ctx
+}
+
+func (g *Group) fail(err error) {
+	g.errOnce.Do(func() {
+		g.err = err
+		g.cancel()
+	})
+}
+
+// Go runs fn in a new goroutine, with the group's context. If the limit has been reached, Go blocks until a running
+// function returns. If the group's context has been cancelled (e.g. a function has failed), fn is not run and the
+// context's error is recorded (unless an error has already been recorded).
+func (g *Group) Go(fn func(ctx context.Context) error) {
+	acquired := false
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+			acquired = true
+		case <-g.ctx.Done():
+		}
+	}
+	if err := g.ctx.Err(); err != nil {
+		if acquired {
+			<-g.sem
+		}
+		g.fail(err)
+		return
+	}
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if g.sem != nil {
+				<-g.sem
+			}
+			g.wg.Done()
+		}()
+		if err := fn(g.ctx); err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+// Wait blocks until all functions have returned, then returns the first recorded error (if any).
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
+
+/*----------------------------------------------------------------------*/
+
+// ErrPoolClosed is returned by Pool.Submit after the pool has been closed.
+//
+// @Available since <<VERSION>>
+var ErrPoolClosed = errors.New("pool is closed")
+
+// Pool is a bounded pool of worker goroutines that process tasks of type T with a handler.
+//
+// @Available since <<VERSION>>
+type Pool[T any] struct {
+	ctx       context.Context
+	handler   func(ctx context.Context, task T)
+	tasks     chan T
+	done      chan struct{} // closed by Close
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewPool creates a new Pool of workers goroutines (workers <= 0 means runtime.NumCPU()) that call handler for each
+// submitted task, with ctx. Once ctx is cancelled, workers stop: they take no new tasks, and a task handed over at the
+// time of the cancellation is discarded without calling handler.
+//
+// @Available since <<VERSION>>
+func NewPool[T any](ctx context.Context, workers int, handler func(ctx context.Context, task T)) *Pool[T] {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	p := &Pool[T]{ctx: ctx, handler: handler, tasks: make(chan T), done: make(chan struct{})}
+	p.wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *Pool[T]) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case task := <-p.tasks:
+			// select picks randomly among ready cases, so the context is checked again before running the task
+			if p.ctx.Err() != nil {
+				return
+			}
+			p.handler(p.ctx, task)
+		case <-p.ctx.Done():
+			return
+		case <-p.done:
+			return
+		}
+	}
+}
+
+// Submit hands a task over to a worker, blocking until a worker is free. It returns ErrPoolClosed if the pool has been
+// closed, and ctx.Err() if ctx is cancelled (or the pool's context error if the pool's context is cancelled) before
+// a worker takes the task.
+//
+// Submit may be called by a handler, including while Close is waiting for the workers.
+func (p *Pool[T]) Submit(ctx context.Context, task T) error {
+	select {
+	case <-p.done:
+		return ErrPoolClosed
+	default:
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := p.ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-p.done:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.ctx.Done():
+		return p.ctx.Err()
+	}
+}
+
+// Close stops accepting tasks and waits for the workers to complete the tasks they have taken.
+// Calling Close more than once is a no-op.
+func (p *Pool[T]) Close() {
+	p.closeOnce.Do(func() { close(p.done) })
+	p.wg.Wait()
+}
+
+/*----------------------------------------------------------------------*/
+
+var errSingleflightPanicked = errors.New("singleflight function panicked")
+
+// singleflightCall is an in-flight or completed call of Singleflight.Do.
+type singleflightCall[V any] struct {
+	done    chan struct{}
+	value   V
+	err     error
+	waiters int // number of callers waiting for the call, guarded by the Singleflight's lock
+}
+
+// Singleflight deduplicates concurrent calls with the same key: while a call for a key is in flight, other calls for
+// the key wait for it and share its result, instead of running their own functions. It is a typed counterpart of
+// golang.org/x/sync/singleflight.
+//
+// The zero value is ready to use. A Singleflight is safe for concurrent use.
+//
+// @Available since <<VERSION>>
+type Singleflight[K comparable, V any] struct {
+	lock  sync.Mutex
+	calls map[K]*singleflightCall[V]
+}
+
+// Do runs fn for the key, unless a call for the key is already in flight, in which case it waits for and returns
+// the result of that call. The third return value is true if the result comes from a call run by another caller.
+//
+// fn is called with the ctx of the caller that runs it. Waiting callers return ctx.Err() if their own ctx is cancelled
+// before the call completes. If fn panics, the panic propagates to its caller, and the waiting callers receive an error.
+func (s *Singleflight[K, V]) Do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (V, error, bool) {
+	s.lock.Lock()
+	if s.calls == nil {
+		s.calls = make(map[K]*singleflightCall[V])
+	}
+	if call, ok := s.calls[key]; ok {
+		call.waiters++
+		s.lock.Unlock()
+		select {
+		case <-call.done:
+			return call.value, call.err, true
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err(), true
+		}
+	}
+	call := &singleflightCall[V]{done: make(chan struct{}), err: errSingleflightPanicked}
+	s.calls[key] = call
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		if s.calls[key] == call {
+			delete(s.calls, key)
+		}
+		s.lock.Unlock()
+		close(call.done)
+	}()
+	call.value, call.err = fn(ctx)
+	return call.value, call.err, false
+}
+
+// Forget makes the next call of Do for the key run its function, instead of waiting for the call in flight.
+func (s *Singleflight[K, V]) Forget(key K) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.calls, key)
+}
+
+/*----------------------------------------------------------------------*/
+
+// Future is the read side of an asynchronous result, which is set once by a Promise (or by Async).
+//
+// @Available since <<VERSION>>
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// Done returns a channel that is closed when the result is available.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Await waits for the result and returns it. It returns ctx.Err() if ctx is cancelled before the result is available.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Promise is the write side of a Future: it resolves (or rejects) the Future exactly once.
+//
+// @Available since <<VERSION>>
+type Promise[T any] struct {
+	future *Future[T]
+	once   sync.Once
+}
+
+// NewPromise creates a new pending Promise.
+//
+// @Available since <<VERSION>>
+func NewPromise[T any]() *Promise[T] {
+	return &Promise[T]{future: &Future[T]{done: make(chan struct{})}}
+}
+
+// Future returns the Future of the promise.
+func (p *Promise[T]) Future() *Future[T] {
+	return p.future
+}
+
+func (p *Promise[T]) complete(value T, err error) bool {
+	completed := false
+	p.once.Do(func() {
+		p.future.value, p.future.err = value, err
+		close(p.future.done)
+		completed = true
+	})
+	return completed
+}
+
+// Resolve completes the Future with a value. The return value is false if the promise has already been completed.
+func (p *Promise[T]) Resolve(value T) bool {
+	return p.complete(value, nil)
+}
+
+// Reject completes the Future with an error. The return value is false if the promise has already been completed.
+func (p *Promise[T]) Reject(err error) bool {
+	var zero T
+	return p.complete(zero, err)
+}
+
+// Async runs fn in a new goroutine and returns a Future of its result. If ctx is cancelled before fn returns,
+// the Future is rejected with ctx.Err() (fn is expected to honour ctx and return early).
+//
+// @Available since <<VERSION>>
+func Async[T any](ctx context.Context, fn func(ctx context.Context) (T, error)) *Future[T] {
+	p := NewPromise[T]()
+	go func() {
+		value, err := fn(ctx)
+		p.complete(value, err)
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.Reject(ctx.Err())
+		case <-p.future.done:
+		}
+	}()
+	return p.future
+}
@@ -0,0 +1,449 @@
+package g18
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap(t *testing.T) {
+	testName := "TestParallelMap"
+	square := func(_ context.Context, v int) (int, error) { return v * v, nil }
+	testData := []struct {
+		input    []int
+		workers  int
+		expected []int
+	}{
+		{nil, 4, []int{}},
+		{[]int{1, 2, 3}, 0, []int{1, 4, 9}},
+		{[]int{1, 2, 3}, 1, []int{1, 4, 9}},
+		{[]int{1, 2, 3}, 10, []int{1, 4, 9}},
+		{Map(make([]int, 100), func(int) int { return 7 }), 8, Map(make([]int, 100), func(int) int { return 49 })},
+	}
+	for _, td := range testData {
+		result, err := ParallelMap(context.Background(), td.input, td.workers, square)
+		if err != nil || !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v, %#v / expected: %#v / received: %#v, %s}", testName, td.input, td.workers, td.expected, result, err)
+		}
+	}
+}
+
+func TestParallelMap_Error(t *testing.T) {
+	testName := "TestParallelMap_Error"
+	errBoom := errors.New("boom")
+	input := make([]int, 1000)
+	for i := range input {
+		input[i] = i
+	}
+	var calls int32
+	var cancelled int32
+	result, err := ParallelMap(context.Background(), input, 4, func(ctx context.Context, v int) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		if v == 10 {
+			return 0, errBoom
+		}
+		if v > 10 {
+			// calls running when the error occurs see the cancellation
+			select {
+			case <-ctx.Done():
+				atomic.AddInt32(&cancelled, 1)
+				return 0, ctx.Err()
+			case <-time.After(time.Millisecond):
+			}
+		}
+		return v, nil
+	})
+	if !errors.Is(err, errBoom) || result != nil {
+		t.Fatalf("%s failed: expected error %s but received (%#v, %s)", testName, errBoom, result, err)
+	}
+	if n := atomic.LoadInt32(&calls); n >= int32(len(input)) {
+		t.Fatalf("%s failed: processing must stop after the first error, but %d calls were made", testName, n)
+	}
+}
+
+func TestParallelMap_ContextCancelled(t *testing.T) {
+	testName := "TestParallelMap_ContextCancelled"
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	cancelled := make(chan struct{})
+	result, err := ParallelMap(ctx, make([]int, 100), 2, func(ctx context.Context, v int) (int, error) {
+		switch n := atomic.AddInt32(&calls, 1); {
+		case n == 5:
+			cancel()
+			close(cancelled)
+		case n > 5:
+			// other calls wait for the cancellation, so that they cannot process all elements before it
+			<-cancelled
+		}
+		return v, nil
+	})
+	if !errors.Is(err, context.Canceled) || result != nil {
+		t.Fatalf("%s failed: expected error %s but received (%#v, %s)", testName, context.Canceled, result, err)
+	}
+	if n := atomic.LoadInt32(&calls); n >= 100 {
+		t.Fatalf("%s failed: processing must stop after cancellation, but %d calls were made", testName, n)
+	}
+}
+
+func TestParallelMap_CancelledAfterCompletion(t *testing.T) {
+	testName := "TestParallelMap_CancelledAfterCompletion"
+	input := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	var calls int32
+	for _, workers := range []int{1, 3, 8} {
+		atomic.StoreInt32(&calls, 0)
+		ctx, cancel := context.WithCancel(context.Background())
+		// the context is cancelled by the call of the last element, after it has been claimed
+		result, err := ParallelMap(ctx, input, workers, func(_ context.Context, v int) (int, error) {
+			if atomic.AddInt32(&calls, 1) == int32(len(input)) {
+				cancel()
+			}
+			return v * 2, nil
+		})
+		if expected := []int{2, 4, 6, 8, 10, 12, 14, 16}; err != nil || !reflect.DeepEqual(result, expected) {
+			t.Fatalf("%s failed: {workers: %d / expected: %#v / received: %#v, %s}", testName, workers, expected, result, err)
+		}
+	}
+}
+
+func TestParallelMap_AlreadyCancelled(t *testing.T) {
+	testName := "TestParallelMap_AlreadyCancelled"
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fn := func(_ context.Context, v int) (int, error) {
+		t.Fatalf("%s failed: fn must not be called", testName)
+		return v, nil
+	}
+	for _, input := range [][]int{nil, {}, {1, 2, 3}} {
+		if result, err := ParallelMap(ctx, input, 2, fn); !errors.Is(err, context.Canceled) || result != nil {
+			t.Fatalf("%s failed: {input: %#v / expected error %s but received (%#v, %s)}", testName, input, context.Canceled, result, err)
+		}
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestGroup(t *testing.T) {
+	testName := "TestGroup"
+	for _, limit := range []int{0, 1, 3} {
+		g, _ := NewGroup(context.Background(), limit)
+		var running, maxRunning, done int32
+		for i := 0; i < 20; i++ {
+			g.Go(func(ctx context.Context) error {
+				n := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				atomic.AddInt32(&done, 1)
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil || done != 20 {
+			t.Fatalf("%s failed: limit %d expected (nil, 20) but received (%s, %d)", testName, limit, err, done)
+		}
+		if limit > 0 && maxRunning > int32(limit) {
+			t.Fatalf("%s failed: limit %d exceeded: %d functions ran at the same time", testName, limit, maxRunning)
+		}
+	}
+}
+
+func TestGroup_Error(t *testing.T) {
+	testName := "TestGroup_Error"
+	errBoom := errors.New("boom")
+	g, ctx := NewGroup(context.Background(), 1)
+	g.Go(func(ctx context.Context) error { return errBoom })
+	var ran int32
+	for i := 0; i < 10; i++ {
+		// once the first function has failed, waiting functions are not run
+		g.Go(func(ctx context.Context) error {
+			atomic.AddInt32(&ran, 1)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}
+	if err := g.Wait(); !errors.Is(err, errBoom) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, errBoom, err)
+	}
+	if ctx.Err() == nil {
+		t.Fatalf("%s failed: group context must be cancelled", testName)
+	}
+	if ran > 1 {
+		t.Fatalf("%s failed: expected at most 1 function to run after the error, but %d ran", testName, ran)
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestPool(t *testing.T) {
+	testName := "TestPool"
+	var lock sync.Mutex
+	sum := 0
+	p := NewPool(context.Background(), 3, func(ctx context.Context, task int) {
+		lock.Lock()
+		defer lock.Unlock()
+		sum += task
+	})
+	for i := 1; i <= 100; i++ {
+		if err := p.Submit(context.Background(), i); err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+	}
+	p.Close()
+	p.Close() // no-op
+	if sum != 5050 {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, 5050, sum)
+	}
+	if err := p.Submit(context.Background(), 1); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, ErrPoolClosed, err)
+	}
+}
+
+func TestPool_ContextCancelled(t *testing.T) {
+	testName := "TestPool_ContextCancelled"
+	release := make(chan struct{})
+	p := NewPool(context.Background(), 1, func(ctx context.Context, task int) { <-release })
+	if err := p.Submit(context.Background(), 1); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+
+	// the only worker is busy: Submit gives up when its context is cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := p.Submit(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, context.DeadlineExceeded, err)
+	}
+	close(release)
+	p.Close()
+
+	// cancelling the pool's context stops the workers
+	poolCtx, poolCancel := context.WithCancel(context.Background())
+	p = NewPool(poolCtx, 2, func(ctx context.Context, task int) {})
+	poolCancel()
+	if err := p.Submit(context.Background(), 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, context.Canceled, err)
+	}
+	p.Close()
+	if err := p.Submit(context.Background(), 1); !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, ErrPoolClosed, err)
+	}
+}
+
+func TestPool_SubmitDuringClose(t *testing.T) {
+	testName := "TestPool_SubmitDuringClose"
+	var p *Pool[int]
+	started, closing := make(chan struct{}), make(chan struct{})
+	resubmitErr := make(chan error, 1)
+	p = NewPool(context.Background(), 2, func(ctx context.Context, task int) {
+		if task == 0 {
+			close(started)
+			<-closing
+			// the handler resubmits to its own pool while Close is waiting for it
+			resubmitErr <- p.Submit(context.Background(), 1)
+		}
+	})
+	if err := p.Submit(context.Background(), 0); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	<-started
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	// Close has closed the pool once Submit reports ErrPoolClosed
+	for !errors.Is(p.Submit(context.Background(), 2), ErrPoolClosed) {
+		time.Sleep(time.Millisecond)
+	}
+	close(closing)
+	select {
+	case <-closed:
+	case <-time.After(10 * time.Second):
+		t.Fatalf("%s failed: Close and Submit deadlocked", testName)
+	}
+	if err := <-resubmitErr; err != nil && !errors.Is(err, ErrPoolClosed) {
+		t.Fatalf("%s failed: unexpected error %s", testName, err)
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+// waitSingleflightWaiters waits until n callers are waiting for the call in flight for the key.
+func waitSingleflightWaiters[K comparable, V any](sf *Singleflight[K, V], key K, n int) {
+	for {
+		sf.lock.Lock()
+		call, ok := sf.calls[key]
+		waiters := 0
+		if ok {
+			waiters = call.waiters
+		}
+		sf.lock.Unlock()
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSingleflight(t *testing.T) {
+	testName := "TestSingleflight"
+	var sf Singleflight[string, int]
+	var calls int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return 42, nil
+	}
+	var wg sync.WaitGroup
+	const n = 10
+	results := make([]int, n)
+	shared := make([]bool, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _, shared[i] = sf.Do(context.Background(), "key", fn)
+		}(i)
+	}
+	waitSingleflightWaiters(&sf, "key", n-1) // let all callers join the call in flight
+	close(release)
+	wg.Wait()
+	if calls != 1 {
+		t.Fatalf("%s failed: expected %#v calls but received %#v", testName, 1, calls)
+	}
+	if v, expected := CountBy(shared, func(b bool) bool { return b }), map[bool]int{true: n - 1, false: 1}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if !All(results, func(v int) bool { return v == 42 }) {
+		t.Fatalf("%s failed: received %#v", testName, results)
+	}
+
+	// completed calls are not cached
+	if v, err, isShared := sf.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 1, nil }); v != 1 || err != nil || isShared {
+		t.Fatalf("%s failed: expected (1, nil, false) but received (%#v, %s, %#v)", testName, v, err, isShared)
+	}
+}
+
+func TestSingleflight_CancelAndForget(t *testing.T) {
+	testName := "TestSingleflight_CancelAndForget"
+	var sf Singleflight[int, string]
+	started, release := make(chan struct{}), make(chan struct{})
+	go sf.Do(context.Background(), 1, func(ctx context.Context) (string, error) {
+		close(started)
+		<-release
+		return "slow", nil
+	})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err, _ := sf.Do(ctx, 1, func(ctx context.Context) (string, error) { return "unused", nil }); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, context.DeadlineExceeded, err)
+	}
+
+	sf.Forget(1)
+	if v, err, shared := sf.Do(context.Background(), 1, func(ctx context.Context) (string, error) { return "fresh", nil }); v != "fresh" || err != nil || shared {
+		t.Fatalf("%s failed: expected (fresh, nil, false) but received (%#v, %s, %#v)", testName, v, err, shared)
+	}
+	close(release)
+}
+
+func TestSingleflight_Panic(t *testing.T) {
+	testName := "TestSingleflight_Panic"
+	var sf Singleflight[string, int]
+	started, release, panicked := make(chan struct{}), make(chan struct{}), make(chan interface{})
+	go func() {
+		defer func() { panicked <- recover() }()
+		sf.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-started
+	const n = 5
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err, _ := sf.Do(context.Background(), "key", func(ctx context.Context) (int, error) { return 1, nil })
+			errs <- err
+		}()
+	}
+	waitSingleflightWaiters(&sf, "key", n)
+	close(release)
+	if r := <-panicked; r == nil {
+		t.Fatalf("%s failed: the panic must propagate to the caller running the function", testName)
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errs; !errors.Is(err, errSingleflightPanicked) {
+			t.Fatalf("%s failed: expected error %s but received %v", testName, errSingleflightPanicked, err)
+		}
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestPromise(t *testing.T) {
+	testName := "TestPromise"
+	p := NewPromise[string]()
+	f := p.Future()
+	select {
+	case <-f.Done():
+		t.Fatalf("%s failed: future must not be done before the promise is completed", testName)
+	default:
+	}
+	go p.Resolve("value")
+	if v, err := f.Await(context.Background()); v != "value" || err != nil {
+		t.Fatalf("%s failed: expected (value, nil) but received (%#v, %s)", testName, v, err)
+	}
+	if p.Resolve("again") || p.Reject(errors.New("too late")) {
+		t.Fatalf("%s failed: a promise must be completed only once", testName)
+	}
+	if v, err := f.Await(context.Background()); v != "value" || err != nil {
+		t.Fatalf("%s failed: expected (value, nil) but received (%#v, %s)", testName, v, err)
+	}
+
+	errBoom := errors.New("boom")
+	p = NewPromise[string]()
+	if !p.Reject(errBoom) {
+		t.Fatalf("%s failed: Reject must complete a pending promise", testName)
+	}
+	if _, err := p.Future().Await(context.Background()); !errors.Is(err, errBoom) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, errBoom, err)
+	}
+
+	// Await honours the context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewPromise[int]().Future().Await(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, context.Canceled, err)
+	}
+}
+
+func TestAsync(t *testing.T) {
+	testName := "TestAsync"
+	f := Async(context.Background(), func(ctx context.Context) (int, error) { return 7, nil })
+	if v, err := f.Await(context.Background()); v != 7 || err != nil {
+		t.Fatalf("%s failed: expected (7, nil) but received (%#v, %s)", testName, v, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	release := make(chan struct{})
+	defer close(release)
+	f = Async(ctx, func(ctx context.Context) (int, error) {
+		<-release // ignores the context
+		return 1, nil
+	})
+	cancel()
+	if _, err := f.Await(context.Background()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("%s failed: expected error %s but received %s", testName, context.Canceled, err)
+	}
+}
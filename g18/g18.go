@@ -33,16 +33,64 @@ func Deduplicate[K Sortable](input []K) []K {
 	return result[:prev+1]
 }
 
-// DeduplicateStable removes duplicated elements from a slice, preserving the order of the elements.
+// DeduplicateStable removes duplicated elements from a slice, preserving the order of the elements
+// (the first occurrence of each element is kept).
+//
+// Note: since <<VERSION>>, this function runs in O(n) time, using a hash set to track seen elements.
 //
 // @Available since v0.1.0
 func DeduplicateStable[K comparable](input []K) []K {
 	if len(input) == 0 {
 		return make([]K, 0)
 	}
-	result := make([]K, 0)
+	result := make([]K, 0, len(input))
+	seen := make(map[K]struct{}, len(input))
+	for _, v := range input {
+		if _, ok := seen[v]; !ok {
+			seen[v] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// DeduplicateBy removes elements with duplicated keys from a slice, preserving the order of the elements
+// (the first element of each key is kept). It is useful for elements that are not comparable, e.g. structs containing
+// slices, or that are identified by a field, e.g. an ID.
+//
+// This function runs in O(n) time.
+//
+// @Available since <<VERSION>>
+func DeduplicateBy[T any, K comparable](input []T, keyFn func(T) K) []T {
+	if len(input) == 0 {
+		return make([]T, 0)
+	}
+	result := make([]T, 0, len(input))
+	seen := make(map[K]struct{}, len(input))
+	for _, v := range input {
+		k := keyFn(v)
+		if _, ok := seen[k]; !ok {
+			seen[k] = struct{}{}
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// DeduplicateFunc removes duplicated elements from a slice, preserving the order of the elements
+// (the first occurrence of each element is kept). Two elements are duplicated if equal returns true.
+//
+// Note: this function runs in O(n²) time as each element is compared against the kept ones. Prefer DeduplicateBy
+// if a comparable key can be derived from the elements.
+//
+// @Available since <<VERSION>>
+func DeduplicateFunc[T any](input []T, equal func(a, b T) bool) []T {
+	if len(input) == 0 {
+		return make([]T, 0)
+	}
+	result := make([]T, 0, len(input))
 	for _, v := range input {
-		if FindInSlice(v, result) == -1 {
+		if FindIndexFunc(result, func(e T) bool { return equal(e, v) }) == -1 {
 			result = append(result, v)
 		}
 	}
@@ -1,6 +1,7 @@
 package g18
 
 import (
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -579,6 +580,149 @@ func TestDeduplicateStable_uintptr(t *testing.T) {
 	}
 }
 
+func TestDeduplicateStable_large(t *testing.T) {
+	testName := "TestDeduplicateStable_large"
+	input := make([]int, 0, 20000)
+	for i := 0; i < 10000; i++ {
+		input = append(input, i, 9999-i)
+	}
+	result := DeduplicateStable(input)
+	if len(result) != 10000 {
+		t.Fatalf("%s failed: expected %#v elements but received %#v", testName, 10000, len(result))
+	}
+	for i, v := range result[:10] {
+		if expected := []int{0, 9999, 1, 9998, 2, 9997, 3, 9996, 4, 9995}[i]; v != expected {
+			t.Fatalf("%s failed: at %d expected %#v but received %#v", testName, i, expected, v)
+		}
+	}
+}
+
+func TestDeduplicateStable_NaN(t *testing.T) {
+	testName := "TestDeduplicateStable_NaN"
+	nan := math.NaN()
+	result := DeduplicateStable([]float64{nan, 1, nan, 1})
+	// NaN is not equal to itself, hence never a duplicate
+	if len(result) != 3 || !math.IsNaN(result[0]) || result[1] != 1 || !math.IsNaN(result[2]) {
+		t.Fatalf("%s failed: received %#v", testName, result)
+	}
+}
+
+type dedupItem struct {
+	ID   int
+	Tags []string // makes the struct non-comparable
+}
+
+func TestDeduplicateBy(t *testing.T) {
+	testName := "TestDeduplicateBy"
+	testData := []struct {
+		input    []dedupItem
+		expected []dedupItem
+	}{
+		{
+			nil, []dedupItem{},
+		},
+		{
+			[]dedupItem{}, []dedupItem{},
+		},
+		{
+			[]dedupItem{{1, []string{"a"}}, {2, nil}}, []dedupItem{{1, []string{"a"}}, {2, nil}},
+		},
+		{
+			[]dedupItem{{3, []string{"a"}}, {1, nil}, {3, []string{"b"}}, {2, nil}, {1, []string{"c"}}}, []dedupItem{{3, []string{"a"}}, {1, nil}, {2, nil}},
+		},
+	}
+	for _, td := range testData {
+		result := DeduplicateBy(td.input, func(v dedupItem) int { return v.ID })
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func TestDeduplicateFunc(t *testing.T) {
+	testName := "TestDeduplicateFunc"
+	testData := []struct {
+		input    []dedupItem
+		expected []dedupItem
+	}{
+		{
+			nil, []dedupItem{},
+		},
+		{
+			[]dedupItem{}, []dedupItem{},
+		},
+		{
+			[]dedupItem{{1, []string{"a"}}, {1, []string{"b"}}}, []dedupItem{{1, []string{"a"}}, {1, []string{"b"}}},
+		},
+		{
+			[]dedupItem{{1, []string{"a"}}, {2, nil}, {1, []string{"a"}}, {2, []string{}}}, []dedupItem{{1, []string{"a"}}, {2, nil}, {2, []string{}}},
+		},
+	}
+	for _, td := range testData {
+		result := DeduplicateFunc(td.input, func(a, b dedupItem) bool { return reflect.DeepEqual(a, b) })
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+// deduplicateStableQuadratic is the former O(n²) implementation of DeduplicateStable, kept for benchmark comparison.
+func deduplicateStableQuadratic[K comparable](input []K) []K {
+	if len(input) == 0 {
+		return make([]K, 0)
+	}
+	result := make([]K, 0)
+	for _, v := range input {
+		if FindInSlice(v, result) == -1 {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func benchmarkDedupInput(n int) []int {
+	input := make([]int, n)
+	for i := range input {
+		input[i] = (i * 7919) % (n / 2) // each value appears twice
+	}
+	return input
+}
+
+func BenchmarkDeduplicateStable_10k(b *testing.B) {
+	input := benchmarkDedupInput(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DeduplicateStable(input)
+	}
+}
+
+func BenchmarkDeduplicateStableQuadratic_10k(b *testing.B) {
+	input := benchmarkDedupInput(10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		deduplicateStableQuadratic(input)
+	}
+}
+
+func BenchmarkDeduplicateStable_200k(b *testing.B) {
+	input := benchmarkDedupInput(200000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DeduplicateStable(input)
+	}
+}
+
+func BenchmarkDeduplicateBy_10k(b *testing.B) {
+	input := make([]dedupItem, 10000)
+	for i := range input {
+		input[i].ID = (i * 7919) % 5000
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		DeduplicateBy(input, func(v dedupItem) int { return v.ID })
+	}
+}
+
 /*----------------------------------------------------------------------*/
 
 func TestFindInSlice_bool(t *testing.T) {
@@ -0,0 +1,251 @@
+package g18
+
+// Heap is a binary heap: Pop always returns the "smallest" element as per the less function, e.g. a min-heap with
+// less(a, b) = a < b, or a max-heap with less(a, b) = a > b. Push and Pop are O(log n), Peek is O(1).
+//
+// Unlike container/heap, Heap is typed and needs no boilerplate. A Heap is not safe for concurrent use.
+//
+// @Available since <<VERSION>>
+type Heap[T any] struct {
+	less  func(a, b T) bool
+	items []T
+}
+
+// NewHeap creates a new Heap ordered by less, optionally initialized with elements (in O(n)).
+//
+// @Available since <<VERSION>>
+func NewHeap[T any](less func(a, b T) bool, elems ...T) *Heap[T] {
+	h := &Heap[T]{less: less, items: append(make([]T, 0, len(elems)), elems...)}
+	for i := len(h.items)/2 - 1; i >= 0; i-- {
+		h.down(i)
+	}
+	return h
+}
+
+// NewMinHeap creates a new Heap that pops the smallest element first.
+//
+// @Available since <<VERSION>>
+func NewMinHeap[T Sortable](elems ...T) *Heap[T] {
+	return NewHeap(func(a, b T) bool { return a < b }, elems...)
+}
+
+// NewMaxHeap creates a new Heap that pops the largest element first.
+//
+// @Available since <<VERSION>>
+func NewMaxHeap[T Sortable](elems ...T) *Heap[T] {
+	return NewHeap(func(a, b T) bool { return a > b }, elems...)
+}
+
+// Len returns the number of elements in the heap.
+func (h *Heap[T]) Len() int {
+	return len(h.items)
+}
+
+// Push adds elements to the heap.
+func (h *Heap[T]) Push(elems ...T) {
+	for _, v := range elems {
+		h.items = append(h.items, v)
+		h.up(len(h.items) - 1)
+	}
+}
+
+// Peek returns the smallest element without removing it. The second return value is false if the heap is empty.
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.items[0], true
+}
+
+// Pop removes and returns the smallest element. The second return value is false if the heap is empty.
+func (h *Heap[T]) Pop() (T, bool) {
+	var zero T
+	n := len(h.items) - 1
+	if n < 0 {
+		return zero, false
+	}
+	result := h.items[0]
+	h.items[0] = h.items[n]
+	h.items[n] = zero // do not retain the popped element
+	h.items = h.items[:n]
+	if n > 0 {
+		h.down(0)
+	}
+	return result, true
+}
+
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.items[i], h.items[parent]) {
+			break
+		}
+		h.items[i], h.items[parent] = h.items[parent], h.items[i]
+		i = parent
+	}
+}
+
+func (h *Heap[T]) down(i int) {
+	n := len(h.items)
+	for {
+		smallest, left, right := i, 2*i+1, 2*i+2
+		if left < n && h.less(h.items[left], h.items[smallest]) {
+			smallest = left
+		}
+		if right < n && h.less(h.items[right], h.items[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		h.items[i], h.items[smallest] = h.items[smallest], h.items[i]
+		i = smallest
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+// PQItem is the handle of an element in a PriorityQueue, returned by PriorityQueue.Push and used to update or remove
+// the element.
+//
+// @Available since <<VERSION>>
+type PQItem[T any] struct {
+	value T
+	index int // position in the queue, -1 if the item is no longer in the queue
+}
+
+// Value returns the element of the item.
+func (item *PQItem[T]) Value() T {
+	return item.value
+}
+
+// PriorityQueue is a priority queue whose elements can be updated or removed via their handles (see PQItem).
+// Pop always returns the "smallest" element as per the less function. Push, Pop, Update and Remove are O(log n).
+//
+// A PriorityQueue is not safe for concurrent use.
+//
+// @Available since <<VERSION>>
+type PriorityQueue[T any] struct {
+	less  func(a, b T) bool
+	items []*PQItem[T]
+}
+
+// NewPriorityQueue creates a new empty PriorityQueue ordered by less.
+//
+// @Available since <<VERSION>>
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// Len returns the number of elements in the queue.
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+// Push adds an element to the queue and returns its handle.
+func (pq *PriorityQueue[T]) Push(v T) *PQItem[T] {
+	item := &PQItem[T]{value: v, index: len(pq.items)}
+	pq.items = append(pq.items, item)
+	pq.up(item.index)
+	return item
+}
+
+// Peek returns the handle of the smallest element without removing it, or nil if the queue is empty.
+func (pq *PriorityQueue[T]) Peek() *PQItem[T] {
+	if len(pq.items) == 0 {
+		return nil
+	}
+	return pq.items[0]
+}
+
+// Pop removes and returns the smallest element. The second return value is false if the queue is empty.
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	item := pq.items[0]
+	pq.removeAt(0)
+	return item.value, true
+}
+
+// Update replaces the element of an item and restores the queue order, e.g. after its priority has changed.
+// The return value is false if the item is not in the queue.
+func (pq *PriorityQueue[T]) Update(item *PQItem[T], v T) bool {
+	if !pq.contains(item) {
+		return false
+	}
+	item.value = v
+	pq.fix(item.index)
+	return true
+}
+
+// Remove removes an item from the queue. The return value is false if the item is not in the queue.
+func (pq *PriorityQueue[T]) Remove(item *PQItem[T]) bool {
+	if !pq.contains(item) {
+		return false
+	}
+	pq.removeAt(item.index)
+	return true
+}
+
+func (pq *PriorityQueue[T]) contains(item *PQItem[T]) bool {
+	return item != nil && item.index >= 0 && item.index < len(pq.items) && pq.items[item.index] == item
+}
+
+func (pq *PriorityQueue[T]) removeAt(i int) {
+	n := len(pq.items) - 1
+	item := pq.items[i]
+	if i != n {
+		pq.swap(i, n)
+	}
+	pq.items[n] = nil
+	pq.items = pq.items[:n]
+	item.index = -1
+	if i != n {
+		pq.fix(i)
+	}
+}
+
+func (pq *PriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(pq.items[i].value, pq.items[parent].value) {
+			break
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down moves the element at i down the heap, and returns true if it has been moved.
+func (pq *PriorityQueue[T]) down(i int) bool {
+	start, n := i, len(pq.items)
+	for {
+		smallest, left, right := i, 2*i+1, 2*i+2
+		if left < n && pq.less(pq.items[left].value, pq.items[smallest].value) {
+			smallest = left
+		}
+		if right < n && pq.less(pq.items[right].value, pq.items[smallest].value) {
+			smallest = right
+		}
+		if smallest == i {
+			return i > start
+		}
+		pq.swap(i, smallest)
+		i = smallest
+	}
+}
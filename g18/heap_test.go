@@ -0,0 +1,204 @@
+package g18
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func drainHeap[T any](h *Heap[T]) []T {
+	result := make([]T, 0, h.Len())
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		result = append(result, v)
+	}
+	return result
+}
+
+func TestHeap_MinMax(t *testing.T) {
+	testName := "TestHeap_MinMax"
+	testData := []struct {
+		input    []int
+		min, max []int
+	}{
+		{nil, []int{}, []int{}},
+		{[]int{1}, []int{1}, []int{1}},
+		{[]int{3, 1, 2}, []int{1, 2, 3}, []int{3, 2, 1}},
+		{[]int{5, 1, 5, 3, 1}, []int{1, 1, 3, 5, 5}, []int{5, 5, 3, 1, 1}},
+	}
+	for _, td := range testData {
+		// initialized with elements
+		if v := drainHeap(NewMinHeap(td.input...)); !reflect.DeepEqual(v, td.min) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.min, v)
+		}
+		// elements pushed one by one
+		h := NewMaxHeap[int]()
+		h.Push(td.input...)
+		if v := drainHeap(h); !reflect.DeepEqual(v, td.max) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.max, v)
+		}
+	}
+}
+
+func TestHeap_PeekPop(t *testing.T) {
+	testName := "TestHeap_PeekPop"
+	h := NewMinHeap("b", "a")
+	if v, ok := h.Peek(); !ok || v != "a" || h.Len() != 2 {
+		t.Fatalf("%s failed: expected (%#v, %#v) but received (%#v, %#v)", testName, "a", true, v, ok)
+	}
+	h.Pop()
+	h.Pop()
+	if v, ok := h.Peek(); ok || v != "" {
+		t.Fatalf("%s failed: expected (%#v, %#v) but received (%#v, %#v)", testName, "", false, v, ok)
+	}
+	if v, ok := h.Pop(); ok || v != "" {
+		t.Fatalf("%s failed: expected (%#v, %#v) but received (%#v, %#v)", testName, "", false, v, ok)
+	}
+}
+
+func TestHeap_CustomLess(t *testing.T) {
+	testName := "TestHeap_CustomLess"
+	type task struct {
+		Name     string
+		Priority int
+	}
+	h := NewHeap(func(a, b task) bool { return a.Priority > b.Priority }, task{"low", 1}, task{"high", 9})
+	h.Push(task{"mid", 5})
+	if v, expected := Map(drainHeap(h), func(t task) string { return t.Name }), []string{"high", "mid", "low"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestHeap_Random(t *testing.T) {
+	testName := "TestHeap_Random"
+	r := rand.New(rand.NewSource(1))
+	for round := 0; round < 20; round++ {
+		input := make([]float64, r.Intn(200))
+		for i := range input {
+			input[i] = r.Float64()
+		}
+		h := NewMinHeap(input[:len(input)/2]...)
+		h.Push(input[len(input)/2:]...)
+		expected := append([]float64{}, input...)
+		sort.Float64s(expected)
+		if v := drainHeap(h); !reflect.DeepEqual(v, expected) {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+		}
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+type pqTask struct {
+	name     string
+	priority int
+}
+
+func drainPriorityQueue(pq *PriorityQueue[pqTask]) []string {
+	result := make([]string, 0, pq.Len())
+	for pq.Len() > 0 {
+		v, _ := pq.Pop()
+		result = append(result, v.name)
+	}
+	return result
+}
+
+func newTaskQueue() *PriorityQueue[pqTask] {
+	return NewPriorityQueue(func(a, b pqTask) bool { return a.priority < b.priority })
+}
+
+func TestPriorityQueue_PushPop(t *testing.T) {
+	testName := "TestPriorityQueue_PushPop"
+	pq := newTaskQueue()
+	if v, ok := pq.Pop(); ok || pq.Peek() != nil || v != (pqTask{}) {
+		t.Fatalf("%s failed: empty queue must have nothing to pop", testName)
+	}
+	pq.Push(pqTask{"c", 3})
+	pq.Push(pqTask{"a", 1})
+	pq.Push(pqTask{"b", 2})
+	if item := pq.Peek(); item == nil || item.Value().name != "a" {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, "a", item)
+	}
+	if v, expected := drainPriorityQueue(pq), []string{"a", "b", "c"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestPriorityQueue_UpdateRemove(t *testing.T) {
+	testName := "TestPriorityQueue_UpdateRemove"
+	pq := newTaskQueue()
+	items := make(map[string]*PQItem[pqTask])
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		items[name] = pq.Push(pqTask{name, i})
+	}
+	if !pq.Update(items["e"], pqTask{"e", -1}) || !pq.Update(items["a"], pqTask{"a", 10}) {
+		t.Fatalf("%s failed: Update must succeed for items in the queue", testName)
+	}
+	if !pq.Remove(items["c"]) || pq.Remove(items["c"]) {
+		t.Fatalf("%s failed: Remove must succeed only once", testName)
+	}
+	if pq.Update(items["c"], pqTask{"c", 0}) {
+		t.Fatalf("%s failed: Update must fail for removed items", testName)
+	}
+	if v, expected := drainPriorityQueue(pq), []string{"e", "b", "d", "a"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if pq.Remove(items["a"]) || pq.Remove(nil) {
+		t.Fatalf("%s failed: Remove must fail for popped items", testName)
+	}
+
+	// handle of another queue
+	other := newTaskQueue()
+	pq.Push(pqTask{"x", 1})
+	if pq.Remove(other.Push(pqTask{"y", 1})) {
+		t.Fatalf("%s failed: Remove must fail for items of another queue", testName)
+	}
+}
+
+func TestPriorityQueue_Random(t *testing.T) {
+	testName := "TestPriorityQueue_Random"
+	r := rand.New(rand.NewSource(1))
+	pq := NewPriorityQueue(func(a, b int) bool { return a < b })
+	expected := make(map[*PQItem[int]]int)
+	for i := 0; i < 2000; i++ {
+		switch op := r.Intn(4); {
+		case op <= 1 || len(expected) == 0:
+			expected[pq.Push(r.Intn(1000))] = 0
+		default:
+			for item := range expected {
+				if op == 2 {
+					pq.Update(item, r.Intn(1000))
+				} else {
+					pq.Remove(item)
+					delete(expected, item)
+				}
+				break
+			}
+		}
+	}
+	values := make([]int, 0, len(expected))
+	for item := range expected {
+		values = append(values, item.Value())
+	}
+	sort.Ints(values)
+	result := make([]int, 0, pq.Len())
+	for pq.Len() > 0 {
+		v, _ := pq.Pop()
+		result = append(result, v)
+	}
+	if !reflect.DeepEqual(result, values) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, values, result)
+	}
+}
+
+func BenchmarkHeap_PushPop(b *testing.B) {
+	h := NewMinHeap[int]()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.Push(i * 7919 % 1000)
+		if h.Len() > 1000 {
+			h.Pop()
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package g18
+
+// listNode is an element of a linkedList.
+type listNode[T any] struct {
+	prev, next *listNode[T]
+	list       *linkedList[T]
+	value      T
+}
+
+// linkedList is a generic doubly linked list, modeled after container/list. It backs the insertion-ordered containers
+// (OrderedSet, OrderedMap) so that elements can be added, removed and moved in O(1).
+//
+// The zero value is an empty list ready to use.
+type linkedList[T any] struct {
+	root listNode[T] // sentinel: root.next is the front, root.prev is the back
+	len  int
+}
+
+func (l *linkedList[T]) lazyInit() {
+	if l.root.next == nil {
+		l.root.next = &l.root
+		l.root.prev = &l.root
+	}
+}
+
+// front returns the first node of the list, or nil if the list is empty.
+func (l *linkedList[T]) front() *listNode[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// back returns the last node of the list, or nil if the list is empty.
+func (l *linkedList[T]) back() *listNode[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// nextOf returns the node after n, or nil if n is the last node.
+func (l *linkedList[T]) nextOf(n *listNode[T]) *listNode[T] {
+	if n.next == &l.root {
+		return nil
+	}
+	return n.next
+}
+
+// prevOf returns the node before n, or nil if n is the first node.
+func (l *linkedList[T]) prevOf(n *listNode[T]) *listNode[T] {
+	if n.prev == &l.root {
+		return nil
+	}
+	return n.prev
+}
+
+// insertAfter links n after at.
+func (l *linkedList[T]) insertAfter(n, at *listNode[T]) *listNode[T] {
+	n.prev = at
+	n.next = at.next
+	n.prev.next = n
+	n.next.prev = n
+	n.list = l
+	l.len++
+	return n
+}
+
+// unlink unlinks n from the list, without clearing its links.
+func (l *linkedList[T]) unlink(n *listNode[T]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	l.len--
+}
+
+// pushFront adds a value to the front of the list and returns its node.
+func (l *linkedList[T]) pushFront(v T) *listNode[T] {
+	l.lazyInit()
+	return l.insertAfter(&listNode[T]{value: v}, &l.root)
+}
+
+// pushBack adds a value to the back of the list and returns its node.
+func (l *linkedList[T]) pushBack(v T) *listNode[T] {
+	l.lazyInit()
+	return l.insertAfter(&listNode[T]{value: v}, l.root.prev)
+}
+
+// remove removes n from the list. It is a no-op if n does not belong to the list.
+func (l *linkedList[T]) remove(n *listNode[T]) {
+	if n.list != l {
+		return
+	}
+	l.unlink(n)
+	n.prev, n.next, n.list = nil, nil, nil
+}
+
+// moveToFront moves n to the front of the list. It is a no-op if n does not belong to the list.
+func (l *linkedList[T]) moveToFront(n *listNode[T]) {
+	if n.list != l || l.root.next == n {
+		return
+	}
+	l.unlink(n)
+	l.insertAfter(n, &l.root)
+}
+
+// moveToBack moves n to the back of the list. It is a no-op if n does not belong to the list.
+func (l *linkedList[T]) moveToBack(n *listNode[T]) {
+	if n.list != l || l.root.prev == n {
+		return
+	}
+	l.unlink(n)
+	l.insertAfter(n, l.root.prev)
+}
+
+// clear removes all nodes from the list.
+func (l *linkedList[T]) clear() {
+	for n := l.front(); n != nil; {
+		next := l.nextOf(n)
+		n.prev, n.next, n.list = nil, nil, nil
+		n = next
+	}
+	l.root.next, l.root.prev, l.len = &l.root, &l.root, 0
+}
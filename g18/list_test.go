@@ -0,0 +1,59 @@
+package g18
+
+import (
+	"reflect"
+	"testing"
+)
+
+func listValues[T any](l *linkedList[T]) []T {
+	result := make([]T, 0, l.len)
+	for n := l.front(); n != nil; n = l.nextOf(n) {
+		result = append(result, n.value)
+	}
+	// walk backward to make sure links in both directions are consistent
+	for i, n := len(result)-1, l.back(); n != nil; i, n = i-1, l.prevOf(n) {
+		if !reflect.DeepEqual(n.value, result[i]) {
+			panic("inconsistent backward links")
+		}
+	}
+	return result
+}
+
+func TestLinkedList(t *testing.T) {
+	testName := "TestLinkedList"
+	l := &linkedList[int]{}
+	if l.front() != nil || l.back() != nil || l.len != 0 {
+		t.Fatalf("%s failed: zero value must be an empty list", testName)
+	}
+
+	n2 := l.pushBack(2)
+	n1 := l.pushFront(1)
+	n3 := l.pushBack(3)
+	if v, expected := listValues(l), []int{1, 2, 3}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	l.moveToFront(n3)
+	l.moveToBack(n1)
+	l.moveToFront(n3) // no-op: already at front
+	if v, expected := listValues(l), []int{3, 2, 1}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	l.remove(n2)
+	l.remove(n2) // no-op: already removed
+	other := &linkedList[int]{}
+	l.moveToFront(other.pushBack(4)) // no-op: node of another list
+	if v, expected := listValues(l), []int{3, 1}; !reflect.DeepEqual(v, expected) || l.len != 2 {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	l.clear()
+	if v := listValues(l); len(v) != 0 || l.len != 0 {
+		t.Fatalf("%s failed: expected empty list but received %#v", testName, v)
+	}
+	l.pushBack(5)
+	if v, expected := listValues(l), []int{5}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
@@ -0,0 +1,274 @@
+package g18
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// orderedMapEntry is a key/value pair of an OrderedMap.
+type orderedMapEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// OrderedMap is a map that remembers the order in which keys were inserted. Setting the value of an existing key does
+// not change its position; MoveToFront and MoveToBack do.
+//
+// Get, Set and Delete are O(1). The zero value is an empty map ready to use. Read-only methods (Len, Get, Keys, Range...)
+// can be called on a nil *OrderedMap, which behaves as an empty map. An OrderedMap is not safe for concurrent use.
+//
+// An OrderedMap is marshaled to JSON as an object whose members are in insertion order (whether it is held by value or
+// by pointer), and unmarshaling a JSON object keeps the order of its members. As with Go maps, keys must be strings, integers or implement encoding.TextMarshaler
+// (and encoding.TextUnmarshaler for unmarshaling).
+//
+// Note: nested JSON objects are unmarshaled as per json.Unmarshal into V. With V being interface{}, they are decoded as
+// map[string]interface{} and lose their member order; use an OrderedMap (or json.RawMessage) as V to keep it.
+//
+// Like a Go map, a copy of a non-empty OrderedMap value shares its entries with the original.
+//
+// @Available since <<VERSION>>
+type OrderedMap[K comparable, V any] struct {
+	m    map[K]*listNode[orderedMapEntry[K, V]]
+	list *linkedList[orderedMapEntry[K, V]] // held by pointer, so that copies of the map (e.g. by MarshalJSON) share the list's sentinel
+}
+
+// NewOrderedMap creates a new empty OrderedMap.
+//
+// @Available since <<VERSION>>
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{m: make(map[K]*listNode[orderedMapEntry[K, V]]), list: &linkedList[orderedMapEntry[K, V]]{}}
+}
+
+// Len returns the number of entries in the map.
+func (om *OrderedMap[K, V]) Len() int {
+	if om == nil {
+		return 0
+	}
+	return len(om.m)
+}
+
+// Get returns the value associated with a key. The second return value is false if the key does not exist.
+func (om *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if om != nil {
+		if n, ok := om.m[key]; ok {
+			return n.value.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Has checks if a key exists in the map.
+func (om *OrderedMap[K, V]) Has(key K) bool {
+	if om == nil {
+		return false
+	}
+	_, ok := om.m[key]
+	return ok
+}
+
+// Set associates a value with a key. A new key is appended to the back of the map; an existing key keeps its position.
+func (om *OrderedMap[K, V]) Set(key K, value V) {
+	if n, ok := om.m[key]; ok {
+		n.value.value = value
+		return
+	}
+	if om.m == nil {
+		om.m, om.list = make(map[K]*listNode[orderedMapEntry[K, V]]), &linkedList[orderedMapEntry[K, V]]{}
+	}
+	om.m[key] = om.list.pushBack(orderedMapEntry[K, V]{key: key, value: value})
+}
+
+// Delete removes a key from the map. The return value is false if the key does not exist.
+func (om *OrderedMap[K, V]) Delete(key K) bool {
+	n, ok := om.m[key]
+	if ok {
+		om.list.remove(n)
+		delete(om.m, key)
+	}
+	return ok
+}
+
+// Clear removes all entries from the map.
+func (om *OrderedMap[K, V]) Clear() {
+	if om.list != nil {
+		om.list.clear()
+	}
+	om.m, om.list = nil, nil
+}
+
+// MoveToFront moves a key to the front of the map. The return value is false if the key does not exist.
+func (om *OrderedMap[K, V]) MoveToFront(key K) bool {
+	n, ok := om.m[key]
+	if ok {
+		om.list.moveToFront(n)
+	}
+	return ok
+}
+
+// MoveToBack moves a key to the back of the map. The return value is false if the key does not exist.
+func (om *OrderedMap[K, V]) MoveToBack(key K) bool {
+	n, ok := om.m[key]
+	if ok {
+		om.list.moveToBack(n)
+	}
+	return ok
+}
+
+// Range calls fn for each entry of the map, in order. Iteration stops if fn returns false.
+// The return value is false if the iteration was stopped by fn.
+//
+// fn may delete the current entry from the map.
+func (om *OrderedMap[K, V]) Range(fn func(key K, value V) bool) bool {
+	if om == nil || om.list == nil {
+		return true
+	}
+	for n := om.list.front(); n != nil; {
+		next := om.list.nextOf(n)
+		if !fn(n.value.key, n.value.value) {
+			return false
+		}
+		n = next
+	}
+	return true
+}
+
+// Keys returns the keys of the map, in order.
+func (om *OrderedMap[K, V]) Keys() []K {
+	result := make([]K, 0, om.Len())
+	om.Range(func(key K, _ V) bool {
+		result = append(result, key)
+		return true
+	})
+	return result
+}
+
+// Values returns the values of the map, in the order of their keys.
+func (om *OrderedMap[K, V]) Values() []V {
+	result := make([]V, 0, om.Len())
+	om.Range(func(_ K, value V) bool {
+		result = append(result, value)
+		return true
+	})
+	return result
+}
+
+// MarshalJSON implements json.Marshaler: the map is marshaled as a JSON object, with members in order.
+//
+// MarshalJSON has a value receiver, so that an OrderedMap is marshaled the same way whether it is held by value or
+// by pointer (a nil *OrderedMap is marshaled as null).
+func (om OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	buf := bytes.NewBufferString("{")
+	var err error
+	om.Range(func(key K, value V) bool {
+		var ks string
+		var kjs, vjs []byte
+		if ks, err = marshalMapKey(key); err != nil {
+			return false
+		}
+		if kjs, err = json.Marshal(ks); err != nil {
+			return false
+		}
+		if vjs, err = json.Marshal(value); err != nil {
+			return false
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(kjs)
+		buf.WriteByte(':')
+		buf.Write(vjs)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler: the map is unmarshaled from a JSON object, replacing its content.
+// Members are added in document order; if a key appears more than once, the last value wins and the key keeps its first
+// position. JSON null leaves the map untouched.
+func (om *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return nil
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("cannot unmarshal %v into %T: expected a JSON object", token, om)
+	}
+	om.Clear()
+	for decoder.More() {
+		if token, err = decoder.Token(); err != nil {
+			return err
+		}
+		var key K
+		if err := unmarshalMapKey(token.(string), &key); err != nil {
+			return err
+		}
+		var value V
+		if err := decoder.Decode(&value); err != nil {
+			return err
+		}
+		om.Set(key, value)
+	}
+	_, err = decoder.Token()
+	return err
+}
+
+// marshalMapKey converts a map key into its JSON object member name, following the rules of encoding/json.
+func marshalMapKey[K comparable](key K) (string, error) {
+	rv := reflect.ValueOf(&key).Elem()
+	if rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+	if tm, ok := any(key).(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %s", rv.Type())
+}
+
+// unmarshalMapKey converts a JSON object member name into a map key, following the rules of encoding/json.
+func unmarshalMapKey[K comparable](s string, key *K) error {
+	rv := reflect.ValueOf(key).Elem()
+	if rv.Kind() == reflect.String {
+		rv.SetString(s)
+		return nil
+	}
+	if tu, ok := any(key).(encoding.TextUnmarshaler); ok {
+		return tu.UnmarshalText([]byte(s))
+	}
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal key %q into %s: %w", s, rv.Type(), err)
+		}
+		rv.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal key %q into %s: %w", s, rv.Type(), err)
+		}
+		rv.SetUint(n)
+		return nil
+	}
+	return fmt.Errorf("unsupported map key type %s", rv.Type())
+}
@@ -0,0 +1,304 @@
+package g18
+
+import (
+	"encoding/json"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestOrderedMap_GetSetDelete(t *testing.T) {
+	testName := "TestOrderedMap_GetSetDelete"
+	var om OrderedMap[string, int] // zero value is ready to use
+	om.Set("c", 3)
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 30) // existing key keeps its position
+	if v, expected := om.Keys(), []string{"c", "a", "b"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := om.Values(), []int{30, 1, 2}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, ok := om.Get("c"); !ok || v != 30 {
+		t.Fatalf("%s failed: expected (%#v, %#v) but received (%#v, %#v)", testName, 30, true, v, ok)
+	}
+	if v, ok := om.Get("x"); ok || v != 0 {
+		t.Fatalf("%s failed: expected (%#v, %#v) but received (%#v, %#v)", testName, 0, false, v, ok)
+	}
+
+	if !om.Delete("a") || om.Delete("a") {
+		t.Fatalf("%s failed: Delete must return true only for existing keys", testName)
+	}
+	om.Set("a", 10) // re-inserted key is appended
+	if v, expected := om.Keys(), []string{"c", "b", "a"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if om.Len() != 3 || !om.Has("b") || om.Has("x") {
+		t.Fatalf("%s failed: unexpected content %#v", testName, om.Keys())
+	}
+
+	om.Clear()
+	if om.Len() != 0 || len(om.Keys()) != 0 {
+		t.Fatalf("%s failed: map must be empty after Clear", testName)
+	}
+	om.Set("z", 26)
+	if v, expected := om.Keys(), []string{"z"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestOrderedMap_nil(t *testing.T) {
+	testName := "TestOrderedMap_nil"
+	var om *OrderedMap[string, int]
+	if _, ok := om.Get("a"); ok || om.Has("a") || om.Len() != 0 || len(om.Keys()) != 0 || len(om.Values()) != 0 {
+		t.Fatalf("%s failed: nil map must behave as an empty map", testName)
+	}
+	if js, err := json.Marshal(om); err != nil || string(js) != "null" {
+		t.Fatalf("%s failed: expected %#v but received %#v / %s", testName, "null", string(js), err)
+	}
+}
+
+func TestOrderedMap_Move(t *testing.T) {
+	testName := "TestOrderedMap_Move"
+	om := NewOrderedMap[int, string]()
+	for i := 1; i <= 4; i++ {
+		om.Set(i, "")
+	}
+	testData := []struct {
+		front    bool
+		key      int
+		ok       bool
+		expected []int
+	}{
+		{true, 3, true, []int{3, 1, 2, 4}},
+		{true, 3, true, []int{3, 1, 2, 4}},
+		{false, 1, true, []int{3, 2, 4, 1}},
+		{false, 1, true, []int{3, 2, 4, 1}},
+		{true, 1, true, []int{1, 3, 2, 4}},
+		{false, 9, false, []int{1, 3, 2, 4}},
+		{true, 9, false, []int{1, 3, 2, 4}},
+	}
+	for _, td := range testData {
+		var ok bool
+		if td.front {
+			ok = om.MoveToFront(td.key)
+		} else {
+			ok = om.MoveToBack(td.key)
+		}
+		if v := om.Keys(); ok != td.ok || !reflect.DeepEqual(v, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: (%#v, %#v) / received: (%#v, %#v)}", testName, td, td.ok, td.expected, ok, v)
+		}
+	}
+}
+
+func TestOrderedMap_Range(t *testing.T) {
+	testName := "TestOrderedMap_Range"
+	om := NewOrderedMap[string, int]()
+	om.Set("a", 1)
+	om.Set("b", 2)
+	om.Set("c", 3)
+	keys := make([]string, 0)
+	if !om.Range(func(k string, v int) bool {
+		keys = append(keys, k)
+		if v%2 == 1 {
+			om.Delete(k) // deleting the current entry while iterating
+		}
+		return true
+	}) {
+		t.Fatalf("%s failed: Range must return true if not stopped", testName)
+	}
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, keys)
+	}
+	if v, expected := om.Keys(), []string{"b"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if om.Range(func(k string, v int) bool { return false }) {
+		t.Fatalf("%s failed: Range must return false if stopped", testName)
+	}
+}
+
+func TestOrderedMap_MarshalJSON(t *testing.T) {
+	testName := "TestOrderedMap_MarshalJSON"
+	inner := NewOrderedMap[string, interface{}]()
+	inner.Set("z", 1)
+	inner.Set("a", []int{1, 2})
+	om := NewOrderedMap[string, interface{}]()
+	om.Set("name", "consu")
+	om.Set("version", 1.5)
+	om.Set("nested", inner)
+	om.Set("tags", nil)
+	om.Set(`quo"te`, true)
+	js, err := json.Marshal(om)
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if v, expected := string(js), `{"name":"consu","version":1.5,"nested":{"z":1,"a":[1,2]},"tags":null,"quo\"te":true}`; v != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	if js, err := json.Marshal(NewOrderedMap[int, int]()); err != nil || string(js) != "{}" {
+		t.Fatalf("%s failed: expected %#v but received %#v / %s", testName, "{}", string(js), err)
+	}
+
+	type myKey struct{ A int }
+	bad := NewOrderedMap[myKey, int]()
+	bad.Set(myKey{1}, 1)
+	if _, err := json.Marshal(bad); err == nil {
+		t.Fatalf("%s failed: expected error marshaling unsupported key type", testName)
+	}
+	badValue := NewOrderedMap[string, interface{}]()
+	badValue.Set("f", func() {})
+	if _, err := json.Marshal(badValue); err == nil {
+		t.Fatalf("%s failed: expected error marshaling unsupported value type", testName)
+	}
+}
+
+func TestOrderedMap_JSONByValue(t *testing.T) {
+	testName := "TestOrderedMap_JSONByValue"
+	type doc struct {
+		M     OrderedMap[string, int]
+		Empty OrderedMap[string, int]
+	}
+	d := doc{}
+	d.M.Set("b", 1)
+	d.M.Set("a", 2)
+	for _, v := range []interface{}{d, &d} {
+		js, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if v, expected := string(js), `{"M":{"b":1,"a":2},"Empty":{}}`; v != expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+		}
+	}
+
+	var decoded doc
+	if err := json.Unmarshal([]byte(`{"M":{"y":1,"x":2}}`), &decoded); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if v, expected := decoded.M.Keys(), []string{"y", "x"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	// copies of a map share its entries
+	copied := decoded.M
+	copied.Set("z", 3)
+	copied.Set("y", 4)
+	if v, expected := decoded.M.Values(), []int{4, 2, 3}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestOrderedMap_UnmarshalJSON(t *testing.T) {
+	testName := "TestOrderedMap_UnmarshalJSON"
+	om := NewOrderedMap[string, json.RawMessage]()
+	om.Set("old", nil)
+	js := `{"z": 1, "a": {"y": 2, "b": 3}, "m": [1, 2], "z": "again"}`
+	if err := json.Unmarshal([]byte(js), om); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if v, expected := om.Keys(), []string{"z", "a", "m"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, _ := om.Get("z"); string(v) != `"again"` {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, `"again"`, string(v))
+	}
+
+	// round-trip keeps document order, including nested ordered maps
+	type doc struct {
+		Fields *OrderedMap[string, *OrderedMap[string, int]] `json:"fields"`
+	}
+	input := `{"fields":{"b":{"z":1,"y":2},"a":{"x":3}}}`
+	var d doc
+	if err := json.Unmarshal([]byte(input), &d); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	output, err := json.Marshal(d)
+	if err != nil || string(output) != input {
+		t.Fatalf("%s failed: expected %#v but received %#v / %s", testName, input, string(output), err)
+	}
+
+	// null leaves the map untouched
+	if err := json.Unmarshal([]byte(`null`), om); err != nil || om.Len() != 3 {
+		t.Fatalf("%s failed: null must leave the map untouched / %s", testName, err)
+	}
+
+	for _, bad := range []string{`[1, 2]`, `"a"`, `{"a": "not a number"}`} {
+		if err := json.Unmarshal([]byte(bad), NewOrderedMap[string, int]()); err == nil {
+			t.Fatalf("%s failed: expected error unmarshaling %s", testName, bad)
+		}
+	}
+}
+
+type myOrderedMapKey string
+
+func TestOrderedMap_JSONKeys(t *testing.T) {
+	testName := "TestOrderedMap_JSONKeys"
+	{
+		om := NewOrderedMap[int8, bool]()
+		om.Set(-2, true)
+		om.Set(1, false)
+		js, err := json.Marshal(om)
+		if err != nil || string(js) != `{"-2":true,"1":false}` {
+			t.Fatalf("%s failed: received %#v / %s", testName, string(js), err)
+		}
+		om2 := NewOrderedMap[int8, bool]()
+		if err := json.Unmarshal(js, om2); err != nil || !reflect.DeepEqual(om2.Keys(), om.Keys()) {
+			t.Fatalf("%s failed: received %#v / %s", testName, om2.Keys(), err)
+		}
+		if err := json.Unmarshal([]byte(`{"300":true}`), om2); err == nil {
+			t.Fatalf("%s failed: expected error unmarshaling an out-of-range key", testName)
+		}
+	}
+	{
+		om := NewOrderedMap[uint, int]()
+		if err := json.Unmarshal([]byte(`{"2":1,"1":2}`), om); err != nil || !reflect.DeepEqual(om.Keys(), []uint{2, 1}) {
+			t.Fatalf("%s failed: received %#v / %s", testName, om.Keys(), err)
+		}
+		if err := json.Unmarshal([]byte(`{"-1":1}`), om); err == nil {
+			t.Fatalf("%s failed: expected error unmarshaling a negative key", testName)
+		}
+	}
+	{
+		om := NewOrderedMap[myOrderedMapKey, int]()
+		if err := json.Unmarshal([]byte(`{"b":1,"a":2}`), om); err != nil || !reflect.DeepEqual(om.Keys(), []myOrderedMapKey{"b", "a"}) {
+			t.Fatalf("%s failed: received %#v / %s", testName, om.Keys(), err)
+		}
+	}
+	{
+		// keys implementing encoding.TextMarshaler/TextUnmarshaler
+		om := NewOrderedMap[netip.Addr, int]()
+		om.Set(netip.MustParseAddr("10.0.0.2"), 2)
+		om.Set(netip.MustParseAddr("10.0.0.1"), 1)
+		js, err := json.Marshal(om)
+		if err != nil || string(js) != `{"10.0.0.2":2,"10.0.0.1":1}` {
+			t.Fatalf("%s failed: received %#v / %s", testName, string(js), err)
+		}
+		om2 := NewOrderedMap[netip.Addr, int]()
+		if err := json.Unmarshal(js, om2); err != nil || !reflect.DeepEqual(om2.Keys(), om.Keys()) {
+			t.Fatalf("%s failed: received %#v / %s", testName, om2.Keys(), err)
+		}
+		if err := json.Unmarshal([]byte(`{"not an ip":1}`), om2); err == nil {
+			t.Fatalf("%s failed: expected error unmarshaling an invalid key", testName)
+		}
+	}
+	{
+		type myKey struct{ A int }
+		if err := json.Unmarshal([]byte(`{"a":1}`), NewOrderedMap[myKey, int]()); err == nil {
+			t.Fatalf("%s failed: expected error unmarshaling unsupported key type", testName)
+		}
+	}
+}
+
+func BenchmarkOrderedMap_Set(b *testing.B) {
+	om := NewOrderedMap[int, int]()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		om.Set(i%1024, i)
+		if i%1024 == 1023 {
+			om.Clear()
+		}
+	}
+}
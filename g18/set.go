@@ -0,0 +1,408 @@
+package g18
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Set is an unordered collection of distinct elements, backed by a map.
+//
+// The zero value is an empty set ready to use. Read-only methods (Len, Has, Slice, Range...) can be called on a nil *Set,
+// which behaves as an empty set. A Set is not safe for concurrent use.
+//
+// A Set is marshaled to JSON as an array, sorted if the elements are Sortable.
+//
+// @Available since <<VERSION>>
+type Set[K comparable] struct {
+	m map[K]struct{}
+}
+
+// NewSet creates a new Set containing the provided elements.
+//
+// @Available since <<VERSION>>
+func NewSet[K comparable](elems ...K) *Set[K] {
+	s := &Set[K]{m: make(map[K]struct{}, len(elems))}
+	s.Add(elems...)
+	return s
+}
+
+// Len returns the number of elements in the set.
+func (s *Set[K]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.m)
+}
+
+// Add adds elements to the set. Elements already in the set are ignored.
+func (s *Set[K]) Add(elems ...K) {
+	if s.m == nil {
+		s.m = make(map[K]struct{}, len(elems))
+	}
+	for _, e := range elems {
+		s.m[e] = struct{}{}
+	}
+}
+
+// Remove removes elements from the set. Elements not in the set are ignored.
+func (s *Set[K]) Remove(elems ...K) {
+	for _, e := range elems {
+		delete(s.m, e)
+	}
+}
+
+// Has checks if an element is in the set.
+func (s *Set[K]) Has(e K) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.m[e]
+	return ok
+}
+
+// Clear removes all elements from the set.
+func (s *Set[K]) Clear() {
+	s.m = nil
+}
+
+// Clone returns a copy of the set.
+func (s *Set[K]) Clone() *Set[K] {
+	result := &Set[K]{m: make(map[K]struct{}, s.Len())}
+	s.Range(func(e K) bool {
+		result.m[e] = struct{}{}
+		return true
+	})
+	return result
+}
+
+// Equal checks if two sets have the same elements.
+func (s *Set[K]) Equal(other *Set[K]) bool {
+	if s.Len() != other.Len() {
+		return false
+	}
+	return s.Range(other.Has)
+}
+
+// Range calls fn for each element of the set, in no particular order. Iteration stops if fn returns false.
+// The return value is false if the iteration was stopped by fn.
+func (s *Set[K]) Range(fn func(e K) bool) bool {
+	if s == nil {
+		return true
+	}
+	for e := range s.m {
+		if !fn(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// Slice returns the elements of the set as a slice. The slice is sorted in ascending order if the elements are Sortable
+// (their underlying type is a string or a number type), so that the result is stable; otherwise the order is unspecified.
+func (s *Set[K]) Slice() []K {
+	result := make([]K, 0, s.Len())
+	s.Range(func(e K) bool {
+		result = append(result, e)
+		return true
+	})
+	sortIfSortable(result)
+	return result
+}
+
+// Union returns a new set containing elements that are in either s or other.
+func (s *Set[K]) Union(other *Set[K]) *Set[K] {
+	result := s.Clone()
+	other.Range(func(e K) bool {
+		result.m[e] = struct{}{}
+		return true
+	})
+	return result
+}
+
+// Intersect returns a new set containing elements that are in both s and other.
+func (s *Set[K]) Intersect(other *Set[K]) *Set[K] {
+	small, big := s, other
+	if small.Len() > big.Len() {
+		small, big = big, small
+	}
+	result := &Set[K]{m: make(map[K]struct{})}
+	small.Range(func(e K) bool {
+		if big.Has(e) {
+			result.m[e] = struct{}{}
+		}
+		return true
+	})
+	return result
+}
+
+// Difference returns a new set containing elements that are in s but not in other.
+func (s *Set[K]) Difference(other *Set[K]) *Set[K] {
+	result := &Set[K]{m: make(map[K]struct{})}
+	s.Range(func(e K) bool {
+		if !other.Has(e) {
+			result.m[e] = struct{}{}
+		}
+		return true
+	})
+	return result
+}
+
+// SymmetricDifference returns a new set containing elements that are in either s or other, but not in both.
+func (s *Set[K]) SymmetricDifference(other *Set[K]) *Set[K] {
+	result := s.Difference(other)
+	other.Range(func(e K) bool {
+		if !s.Has(e) {
+			result.m[e] = struct{}{}
+		}
+		return true
+	})
+	return result
+}
+
+// MarshalJSON implements json.Marshaler: the set is marshaled as an array of its elements (see Slice).
+//
+// MarshalJSON has a value receiver, so that a Set is marshaled the same way whether it is held by value or by pointer.
+func (s Set[K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Slice())
+}
+
+// UnmarshalJSON implements json.Unmarshaler: the set is unmarshaled from an array of elements, replacing its content.
+// Duplicated elements in the array are ignored.
+func (s *Set[K]) UnmarshalJSON(data []byte) error {
+	var elems []K
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	s.m = nil
+	s.Add(elems...)
+	return nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// OrderedSet is a collection of distinct elements that remembers the order in which elements were added.
+// Adding an element that is already in the set does not change its position.
+//
+// Add, Remove and Has are O(1). The zero value is an empty set ready to use. Read-only methods (Len, Has, Slice, Range...)
+// can be called on a nil *OrderedSet, which behaves as an empty set. An OrderedSet is not safe for concurrent use.
+//
+// An OrderedSet is marshaled to JSON as an array, in insertion order, whether it is held by value or by pointer.
+// Like a Go map, a copy of a non-empty OrderedSet value shares its elements with the original; use Clone to get an
+// independent copy.
+//
+// @Available since <<VERSION>>
+type OrderedSet[K comparable] struct {
+	m    map[K]*listNode[K]
+	list *linkedList[K] // held by pointer, so that copies of the set (e.g. by MarshalJSON) share the list's sentinel
+}
+
+// newOrderedSet creates a new empty OrderedSet, with room for capacity elements.
+func newOrderedSet[K comparable](capacity int) *OrderedSet[K] {
+	return &OrderedSet[K]{m: make(map[K]*listNode[K], capacity), list: &linkedList[K]{}}
+}
+
+// NewOrderedSet creates a new OrderedSet containing the provided elements, in order.
+//
+// @Available since <<VERSION>>
+func NewOrderedSet[K comparable](elems ...K) *OrderedSet[K] {
+	s := newOrderedSet[K](len(elems))
+	s.Add(elems...)
+	return s
+}
+
+// Len returns the number of elements in the set.
+func (s *OrderedSet[K]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.m)
+}
+
+// Add appends elements to the set. Elements already in the set are ignored.
+func (s *OrderedSet[K]) Add(elems ...K) {
+	if s.m == nil {
+		s.m, s.list = make(map[K]*listNode[K], len(elems)), &linkedList[K]{}
+	}
+	for _, e := range elems {
+		if _, ok := s.m[e]; !ok {
+			s.m[e] = s.list.pushBack(e)
+		}
+	}
+}
+
+// Remove removes elements from the set. Elements not in the set are ignored.
+func (s *OrderedSet[K]) Remove(elems ...K) {
+	for _, e := range elems {
+		if n, ok := s.m[e]; ok {
+			s.list.remove(n)
+			delete(s.m, e)
+		}
+	}
+}
+
+// Has checks if an element is in the set.
+func (s *OrderedSet[K]) Has(e K) bool {
+	if s == nil {
+		return false
+	}
+	_, ok := s.m[e]
+	return ok
+}
+
+// Clear removes all elements from the set.
+func (s *OrderedSet[K]) Clear() {
+	if s.list != nil {
+		s.list.clear()
+	}
+	s.m, s.list = nil, nil
+}
+
+// Clone returns a copy of the set.
+func (s *OrderedSet[K]) Clone() *OrderedSet[K] {
+	result := newOrderedSet[K](s.Len())
+	s.Range(func(e K) bool {
+		result.m[e] = result.list.pushBack(e)
+		return true
+	})
+	return result
+}
+
+// Equal checks if two sets have the same elements, regardless of their order.
+func (s *OrderedSet[K]) Equal(other *OrderedSet[K]) bool {
+	if s.Len() != other.Len() {
+		return false
+	}
+	return s.Range(other.Has)
+}
+
+// Range calls fn for each element of the set, in insertion order. Iteration stops if fn returns false.
+// The return value is false if the iteration was stopped by fn.
+//
+// fn may remove the current element from the set.
+func (s *OrderedSet[K]) Range(fn func(e K) bool) bool {
+	if s == nil || s.list == nil {
+		return true
+	}
+	for n := s.list.front(); n != nil; {
+		next := s.list.nextOf(n)
+		if !fn(n.value) {
+			return false
+		}
+		n = next
+	}
+	return true
+}
+
+// Slice returns the elements of the set as a slice, in insertion order.
+func (s *OrderedSet[K]) Slice() []K {
+	result := make([]K, 0, s.Len())
+	s.Range(func(e K) bool {
+		result = append(result, e)
+		return true
+	})
+	return result
+}
+
+// Union returns a new set containing elements that are in either s or other: elements of s in their order, followed by
+// elements only in other in their order.
+func (s *OrderedSet[K]) Union(other *OrderedSet[K]) *OrderedSet[K] {
+	result := s.Clone()
+	other.Range(func(e K) bool {
+		result.Add(e)
+		return true
+	})
+	return result
+}
+
+// Intersect returns a new set containing elements that are in both s and other, in the order of s.
+func (s *OrderedSet[K]) Intersect(other *OrderedSet[K]) *OrderedSet[K] {
+	result := newOrderedSet[K](0)
+	s.Range(func(e K) bool {
+		if other.Has(e) {
+			result.m[e] = result.list.pushBack(e)
+		}
+		return true
+	})
+	return result
+}
+
+// Difference returns a new set containing elements that are in s but not in other, in the order of s.
+func (s *OrderedSet[K]) Difference(other *OrderedSet[K]) *OrderedSet[K] {
+	result := newOrderedSet[K](0)
+	s.Range(func(e K) bool {
+		if !other.Has(e) {
+			result.m[e] = result.list.pushBack(e)
+		}
+		return true
+	})
+	return result
+}
+
+// SymmetricDifference returns a new set containing elements that are in either s or other, but not in both:
+// elements only in s in their order, followed by elements only in other in their order.
+func (s *OrderedSet[K]) SymmetricDifference(other *OrderedSet[K]) *OrderedSet[K] {
+	result := s.Difference(other)
+	other.Range(func(e K) bool {
+		if !s.Has(e) {
+			result.m[e] = result.list.pushBack(e)
+		}
+		return true
+	})
+	return result
+}
+
+// MarshalJSON implements json.Marshaler: the set is marshaled as an array of its elements, in insertion order.
+//
+// MarshalJSON has a value receiver, so that an OrderedSet is marshaled the same way whether it is held by value or
+// by pointer.
+func (s OrderedSet[K]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Slice())
+}
+
+// UnmarshalJSON implements json.Unmarshaler: the set is unmarshaled from an array of elements, replacing its content.
+// Duplicated elements in the array are ignored.
+func (s *OrderedSet[K]) UnmarshalJSON(data []byte) error {
+	var elems []K
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	s.Clear()
+	s.Add(elems...)
+	return nil
+}
+
+/*----------------------------------------------------------------------*/
+
+// sortIfSortable sorts a slice in ascending order if its elements are Sortable, i.e. their underlying type is a string
+// or a number type. Otherwise the slice is left untouched.
+func sortIfSortable[K comparable](s []K) {
+	switch v := any(s).(type) {
+	case []string:
+		sort.Strings(v)
+		return
+	case []int:
+		sort.Ints(v)
+		return
+	case []float64:
+		sort.Float64s(v)
+		return
+	}
+
+	// named types (e.g. time.Duration) and other number types: compare via reflection
+	rv := reflect.ValueOf(s)
+	var less func(i, j int) bool
+	switch rv.Type().Elem().Kind() {
+	case reflect.String:
+		less = func(i, j int) bool { return rv.Index(i).String() < rv.Index(j).String() }
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less = func(i, j int) bool { return rv.Index(i).Int() < rv.Index(j).Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		less = func(i, j int) bool { return rv.Index(i).Uint() < rv.Index(j).Uint() }
+	case reflect.Float32, reflect.Float64:
+		less = func(i, j int) bool { return rv.Index(i).Float() < rv.Index(j).Float() }
+	default:
+		return
+	}
+	sort.Slice(s, less)
+}
@@ -0,0 +1,339 @@
+package g18
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSet_AddRemoveHas(t *testing.T) {
+	testName := "TestSet_AddRemoveHas"
+	var s Set[string] // zero value is ready to use
+	s.Add("a", "b", "a", "c")
+	if s.Len() != 3 {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, 3, s.Len())
+	}
+	s.Remove("b", "x")
+	for e, expected := range map[string]bool{"a": true, "b": false, "c": true, "x": false} {
+		if v := s.Has(e); v != expected {
+			t.Fatalf("%s failed: Has(%#v) expected %#v but received %#v", testName, e, expected, v)
+		}
+	}
+	s.Clear()
+	if s.Len() != 0 || s.Has("a") {
+		t.Fatalf("%s failed: set must be empty after Clear", testName)
+	}
+	s.Add("z")
+	if !s.Has("z") {
+		t.Fatalf("%s failed: set must be usable after Clear", testName)
+	}
+}
+
+func TestSet_nil(t *testing.T) {
+	testName := "TestSet_nil"
+	var s *Set[int]
+	if s.Len() != 0 || s.Has(1) || len(s.Slice()) != 0 || !s.Equal(NewSet[int]()) {
+		t.Fatalf("%s failed: nil set must behave as an empty set", testName)
+	}
+	if v, expected := NewSet(1, 2).Union(s).Slice(), []int{1, 2}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestSet_Operations(t *testing.T) {
+	testName := "TestSet_Operations"
+	a, b := NewSet(1, 2, 3, 4), NewSet(3, 4, 5)
+	testData := []struct {
+		name     string
+		result   *Set[int]
+		expected []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4, 5}},
+		{"Intersect", a.Intersect(b), []int{3, 4}},
+		{"Intersect(reversed)", b.Intersect(a), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"Difference(reversed)", b.Difference(a), []int{5}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"Intersect(empty)", a.Intersect(NewSet[int]()), []int{}},
+	}
+	for _, td := range testData {
+		if v := td.result.Slice(); !reflect.DeepEqual(v, td.expected) {
+			t.Fatalf("%s failed: %s expected %#v but received %#v", testName, td.name, td.expected, v)
+		}
+	}
+
+	// operations must not modify the operands
+	if v, expected := a.Slice(), []int{1, 2, 3, 4}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := b.Slice(), []int{3, 4, 5}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestSet_EqualClone(t *testing.T) {
+	testName := "TestSet_EqualClone"
+	a := NewSet("x", "y")
+	b := a.Clone()
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Fatalf("%s failed: clone must be equal to the original", testName)
+	}
+	b.Add("z")
+	if a.Equal(b) || a.Has("z") {
+		t.Fatalf("%s failed: clone must be independent of the original", testName)
+	}
+	b.Remove("x")
+	if a.Equal(b) {
+		t.Fatalf("%s failed: sets with the same size but different elements must not be equal", testName)
+	}
+}
+
+func TestSet_Range(t *testing.T) {
+	testName := "TestSet_Range"
+	s := NewSet(1, 2, 3)
+	sum := 0
+	if !s.Range(func(e int) bool { sum += e; return true }) || sum != 6 {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, 6, sum)
+	}
+	count := 0
+	if s.Range(func(e int) bool { count++; return false }) || count != 1 {
+		t.Fatalf("%s failed: iteration must stop when fn returns false", testName)
+	}
+}
+
+type mySetString string
+
+func TestSet_Slice(t *testing.T) {
+	testName := "TestSet_Slice"
+	if v, expected := NewSet("c", "a", "b").Slice(), []string{"a", "b", "c"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := NewSet[mySetString]("c", "a", "b").Slice(), []mySetString{"a", "b", "c"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := NewSet(3, -1, 2).Slice(), []int{-1, 2, 3}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := NewSet[int8](3, -1, 2).Slice(), []int8{-1, 2, 3}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := NewSet[uint16](3, 1, 2).Slice(), []uint16{1, 2, 3}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := NewSet(3.4, 1.2, 2.3).Slice(), []float64{1.2, 2.3, 3.4}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := NewSet[float32](3.4, 1.2, 2.3).Slice(), []float32{1.2, 2.3, 3.4}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if v, expected := NewSet(time.Second, time.Millisecond, time.Minute).Slice(), []time.Duration{time.Millisecond, time.Second, time.Minute}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	// non-sortable elements: order is unspecified
+	type point struct{ X, Y int }
+	v := NewSet(point{1, 2}, point{3, 4}).Slice()
+	if len(v) != 2 || !NewSet(v...).Equal(NewSet(point{3, 4}, point{1, 2})) {
+		t.Fatalf("%s failed: received %#v", testName, v)
+	}
+}
+
+func TestSet_JSON(t *testing.T) {
+	testName := "TestSet_JSON"
+	type doc struct {
+		Tags *Set[string] `json:"tags"`
+	}
+	js, err := json.Marshal(doc{Tags: NewSet("go", "json", "api")})
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if v, expected := string(js), `{"tags":["api","go","json"]}`; v != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	var d doc
+	if err := json.Unmarshal([]byte(`{"tags":["x","y","x"]}`), &d); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if !d.Tags.Equal(NewSet("x", "y")) {
+		t.Fatalf("%s failed: received %#v", testName, d.Tags.Slice())
+	}
+
+	s := NewSet(1, 2)
+	if err := json.Unmarshal([]byte(`[3]`), s); err != nil || !s.Equal(NewSet(3)) {
+		t.Fatalf("%s failed: unmarshaling must replace content of the set, received %#v / %s", testName, s.Slice(), err)
+	}
+	if err := json.Unmarshal([]byte(`{"a":1}`), s); err == nil {
+		t.Fatalf("%s failed: expected error unmarshaling a non-array", testName)
+	}
+}
+
+func TestSet_JSONByValue(t *testing.T) {
+	testName := "TestSet_JSONByValue"
+	type doc struct {
+		Tags Set[string] `json:"tags"`
+	}
+	d := doc{Tags: *NewSet("go", "json", "api")}
+	for _, v := range []interface{}{d, &d} {
+		js, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if v, expected := string(js), `{"tags":["api","go","json"]}`; v != expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+		}
+	}
+
+	var decoded doc
+	if err := json.Unmarshal([]byte(`{"tags":["x","y","x"]}`), &decoded); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if !decoded.Tags.Equal(NewSet("x", "y")) {
+		t.Fatalf("%s failed: received %#v", testName, decoded.Tags.Slice())
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+func TestOrderedSet_AddRemoveHas(t *testing.T) {
+	testName := "TestOrderedSet_AddRemoveHas"
+	var s OrderedSet[string] // zero value is ready to use
+	s.Add("c", "a", "c", "b")
+	if v, expected := s.Slice(), []string{"c", "a", "b"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	s.Remove("a", "x")
+	s.Add("a", "c") // "c" keeps its position, "a" is appended
+	if v, expected := s.Slice(), []string{"c", "b", "a"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if !s.Has("b") || s.Has("x") || s.Len() != 3 {
+		t.Fatalf("%s failed: unexpected content %#v", testName, s.Slice())
+	}
+	s.Clear()
+	if s.Len() != 0 || len(s.Slice()) != 0 {
+		t.Fatalf("%s failed: set must be empty after Clear", testName)
+	}
+	s.Add("z")
+	if v, expected := s.Slice(), []string{"z"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestOrderedSet_nil(t *testing.T) {
+	testName := "TestOrderedSet_nil"
+	var s *OrderedSet[int]
+	if s.Len() != 0 || s.Has(1) || len(s.Slice()) != 0 || !s.Equal(NewOrderedSet[int]()) {
+		t.Fatalf("%s failed: nil set must behave as an empty set", testName)
+	}
+}
+
+func TestOrderedSet_Operations(t *testing.T) {
+	testName := "TestOrderedSet_Operations"
+	a, b := NewOrderedSet(4, 1, 3, 2), NewOrderedSet(5, 3, 4)
+	testData := []struct {
+		name     string
+		result   *OrderedSet[int]
+		expected []int
+	}{
+		{"Union", a.Union(b), []int{4, 1, 3, 2, 5}},
+		{"Union(reversed)", b.Union(a), []int{5, 3, 4, 1, 2}},
+		{"Intersect", a.Intersect(b), []int{4, 3}},
+		{"Intersect(reversed)", b.Intersect(a), []int{3, 4}},
+		{"Difference", a.Difference(b), []int{1, 2}},
+		{"SymmetricDifference", a.SymmetricDifference(b), []int{1, 2, 5}},
+		{"SymmetricDifference(reversed)", b.SymmetricDifference(a), []int{5, 1, 2}},
+	}
+	for _, td := range testData {
+		if v := td.result.Slice(); !reflect.DeepEqual(v, td.expected) {
+			t.Fatalf("%s failed: %s expected %#v but received %#v", testName, td.name, td.expected, v)
+		}
+	}
+	if v, expected := a.Slice(), []int{4, 1, 3, 2}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
+
+func TestOrderedSet_EqualCloneRange(t *testing.T) {
+	testName := "TestOrderedSet_EqualCloneRange"
+	a := NewOrderedSet(1, 2, 3)
+	if !a.Equal(NewOrderedSet(3, 2, 1)) {
+		t.Fatalf("%s failed: equality must not depend on order", testName)
+	}
+	b := a.Clone()
+	b.Add(4)
+	if a.Equal(b) || a.Has(4) {
+		t.Fatalf("%s failed: clone must be independent of the original", testName)
+	}
+
+	// removing the current element while iterating
+	b.Range(func(e int) bool {
+		if e%2 == 0 {
+			b.Remove(e)
+		}
+		return true
+	})
+	if v, expected := b.Slice(), []int{1, 3}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	count := 0
+	if a.Range(func(e int) bool { count++; return false }) || count != 1 {
+		t.Fatalf("%s failed: iteration must stop when fn returns false", testName)
+	}
+}
+
+func TestOrderedSet_JSON(t *testing.T) {
+	testName := "TestOrderedSet_JSON"
+	js, err := json.Marshal(NewOrderedSet("go", "json", "api"))
+	if err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if v, expected := string(js), `["go","json","api"]`; v != expected {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	s := NewOrderedSet("old")
+	if err := json.Unmarshal([]byte(`["y","x","y","z"]`), s); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if v, expected := s.Slice(), []string{"y", "x", "z"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+	if err := json.Unmarshal([]byte(`"x"`), s); err == nil {
+		t.Fatalf("%s failed: expected error unmarshaling a non-array", testName)
+	}
+}
+
+func TestOrderedSet_JSONByValue(t *testing.T) {
+	testName := "TestOrderedSet_JSONByValue"
+	type doc struct {
+		Tags  OrderedSet[string] `json:"tags"`
+		Empty OrderedSet[int]    `json:"empty"`
+	}
+	d := doc{Tags: *NewOrderedSet("go", "json", "api")}
+	for _, v := range []interface{}{d, &d} {
+		js, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("%s failed: %s", testName, err)
+		}
+		if v, expected := string(js), `{"tags":["go","json","api"],"empty":[]}`; v != expected {
+			t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+		}
+	}
+
+	var decoded doc
+	if err := json.Unmarshal([]byte(`{"tags":["y","x","y"]}`), &decoded); err != nil {
+		t.Fatalf("%s failed: %s", testName, err)
+	}
+	if v, expected := decoded.Tags.Slice(), []string{"y", "x"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	// copies of a set share its elements
+	copied := decoded.Tags
+	copied.Add("z")
+	if v, expected := decoded.Tags.Slice(), []string{"y", "x", "z"}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}
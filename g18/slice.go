@@ -0,0 +1,210 @@
+package g18
+
+// Map returns a new slice containing the results of applying fn to each element of the input.
+//
+// @Available since <<VERSION>>
+func Map[T, R any](input []T, fn func(T) R) []R {
+	result := make([]R, len(input))
+	for i, v := range input {
+		result[i] = fn(v)
+	}
+	return result
+}
+
+// Filter returns a new slice containing the elements of the input that satisfy pred, in order.
+//
+// @Available since <<VERSION>>
+func Filter[T any](input []T, pred func(T) bool) []T {
+	result := make([]T, 0)
+	for _, v := range input {
+		if pred(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Reduce folds the input into a single value: fn is called for each element with the accumulated value (starting with
+// initial) and the element, and returns the new accumulated value.
+//
+// @Available since <<VERSION>>
+func Reduce[T, A any](input []T, initial A, fn func(A, T) A) A {
+	result := initial
+	for _, v := range input {
+		result = fn(result, v)
+	}
+	return result
+}
+
+// FlatMap applies fn to each element of the input and concatenates the results into a new slice.
+//
+// @Available since <<VERSION>>
+func FlatMap[T, R any](input []T, fn func(T) []R) []R {
+	result := make([]R, 0, len(input))
+	for _, v := range input {
+		result = append(result, fn(v)...)
+	}
+	return result
+}
+
+// GroupBy groups elements of the input by the keys returned by keyFn. Elements in each group are in input order.
+//
+// @Available since <<VERSION>>
+func GroupBy[T any, K comparable](input []T, keyFn func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, v := range input {
+		k := keyFn(v)
+		result[k] = append(result[k], v)
+	}
+	return result
+}
+
+// Partition splits the input into 2 new slices: elements that satisfy pred and elements that do not, both in input order.
+//
+// @Available since <<VERSION>>
+func Partition[T any](input []T, pred func(T) bool) (matched, unmatched []T) {
+	matched, unmatched = make([]T, 0), make([]T, 0)
+	for _, v := range input {
+		if pred(v) {
+			matched = append(matched, v)
+		} else {
+			unmatched = append(unmatched, v)
+		}
+	}
+	return matched, unmatched
+}
+
+// Chunk splits the input into consecutive chunks of size elements; the last chunk may be smaller.
+//
+// The chunks are sub-slices of the input (no element is copied), with capacities capped to their lengths so that
+// appending to a chunk does not overwrite the next one. This function panics if size is not positive.
+//
+// @Available since <<VERSION>>
+func Chunk[T any](input []T, size int) [][]T {
+	if size <= 0 {
+		panic("chunk size must be positive")
+	}
+	result := make([][]T, 0, (len(input)+size-1)/size)
+	for i := 0; i < len(input); i += size {
+		end := Min(i+size, len(input))
+		result = append(result, input[i:end:end])
+	}
+	return result
+}
+
+// Window returns all sliding windows of size consecutive elements of the input, e.g. Window([1,2,3], 2) returns
+// [[1,2], [2,3]]. An empty result is returned if the input has fewer than size elements.
+//
+// The windows are sub-slices of the input (no element is copied), with capacities capped to their lengths.
+// This function panics if size is not positive.
+//
+// @Available since <<VERSION>>
+func Window[T any](input []T, size int) [][]T {
+	if size <= 0 {
+		panic("window size must be positive")
+	}
+	if len(input) < size {
+		return make([][]T, 0)
+	}
+	result := make([][]T, 0, len(input)-size+1)
+	for i := 0; i+size <= len(input); i++ {
+		result = append(result, input[i:i+size:i+size])
+	}
+	return result
+}
+
+// Pair holds 2 values, e.g. the elements of 2 slices at the same position (see Zip).
+//
+// @Available since <<VERSION>>
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip pairs up elements of 2 slices at the same positions. The result has the length of the shorter slice.
+//
+// @Available since <<VERSION>>
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := Min(len(a), len(b))
+	result := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+// Unzip splits a slice of pairs into 2 slices, the reverse of Zip.
+//
+// @Available since <<VERSION>>
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	a, b := make([]A, len(pairs)), make([]B, len(pairs))
+	for i, p := range pairs {
+		a[i], b[i] = p.First, p.Second
+	}
+	return a, b
+}
+
+// Any checks if at least one element of the input satisfies pred. It returns false for an empty input.
+//
+// @Available since <<VERSION>>
+func Any[T any](input []T, pred func(T) bool) bool {
+	return FindIndexFunc(input, pred) >= 0
+}
+
+// All checks if all elements of the input satisfy pred. It returns true for an empty input.
+//
+// @Available since <<VERSION>>
+func All[T any](input []T, pred func(T) bool) bool {
+	for _, v := range input {
+		if !pred(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// CountBy counts elements of the input by the keys returned by keyFn.
+//
+// @Available since <<VERSION>>
+func CountBy[T any, K comparable](input []T, keyFn func(T) K) map[K]int {
+	result := make(map[K]int)
+	for _, v := range input {
+		result[keyFn(v)]++
+	}
+	return result
+}
+
+// KeyBy indexes elements of the input by the keys returned by keyFn. If several elements have the same key,
+// the last one wins.
+//
+// @Available since <<VERSION>>
+func KeyBy[T any, K comparable](input []T, keyFn func(T) K) map[K]T {
+	result := make(map[K]T, len(input))
+	for _, v := range input {
+		result[keyFn(v)] = v
+	}
+	return result
+}
+
+// Reverse returns a new slice containing the elements of the input in reverse order. The input is not modified.
+//
+// @Available since <<VERSION>>
+func Reverse[T any](input []T) []T {
+	result := make([]T, len(input))
+	for i, v := range input {
+		result[len(input)-1-i] = v
+	}
+	return result
+}
+
+// FindIndexFunc returns the position of the first element in haystack that satisfies pred. -1 is returned if not found.
+//
+// @Available since <<VERSION>>
+func FindIndexFunc[T any](haystack []T, pred func(T) bool) int {
+	for i, v := range haystack {
+		if pred(v) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,329 @@
+package g18
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func isEven(v int) bool { return v%2 == 0 }
+
+func TestMap(t *testing.T) {
+	testName := "TestMap"
+	testData := []struct {
+		input    []int
+		expected []string
+	}{
+		{nil, []string{}},
+		{[]int{}, []string{}},
+		{[]int{1, 2, 3}, []string{"1", "2", "3"}},
+	}
+	for _, td := range testData {
+		result := Map(td.input, strconv.Itoa)
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	testName := "TestFilter"
+	testData := []struct {
+		input    []int
+		expected []int
+	}{
+		{nil, []int{}},
+		{[]int{1, 3}, []int{}},
+		{[]int{1, 2, 3, 4}, []int{2, 4}},
+		{[]int{4, 2}, []int{4, 2}},
+	}
+	for _, td := range testData {
+		result := Filter(td.input, isEven)
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func TestReduce(t *testing.T) {
+	testName := "TestReduce"
+	testData := []struct {
+		input    []int
+		expected string
+	}{
+		{nil, ">"},
+		{[]int{1}, ">1"},
+		{[]int{1, 2, 3}, ">123"},
+	}
+	for _, td := range testData {
+		result := Reduce(td.input, ">", func(acc string, v int) string { return acc + strconv.Itoa(v) })
+		if result != td.expected {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func TestFlatMap(t *testing.T) {
+	testName := "TestFlatMap"
+	testData := []struct {
+		input    []string
+		expected []string
+	}{
+		{nil, []string{}},
+		{[]string{""}, []string{}},
+		{[]string{"a,b", "", "c"}, []string{"a", "b", "c"}},
+	}
+	for _, td := range testData {
+		result := FlatMap(td.input, func(s string) []string {
+			if s == "" {
+				return nil
+			}
+			return strings.Split(s, ",")
+		})
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	testName := "TestGroupBy"
+	testData := []struct {
+		input    []string
+		expected map[int][]string
+	}{
+		{nil, map[int][]string{}},
+		{[]string{"a", "bb", "c", "dd", "eee"}, map[int][]string{1: {"a", "c"}, 2: {"bb", "dd"}, 3: {"eee"}}},
+	}
+	for _, td := range testData {
+		result := GroupBy(td.input, func(s string) int { return len(s) })
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func TestPartition(t *testing.T) {
+	testName := "TestPartition"
+	testData := []struct {
+		input              []int
+		matched, unmatched []int
+	}{
+		{nil, []int{}, []int{}},
+		{[]int{1, 2, 3, 4, 5}, []int{2, 4}, []int{1, 3, 5}},
+		{[]int{2}, []int{2}, []int{}},
+	}
+	for _, td := range testData {
+		matched, unmatched := Partition(td.input, isEven)
+		if !reflect.DeepEqual(matched, td.matched) || !reflect.DeepEqual(unmatched, td.unmatched) {
+			t.Fatalf("%s failed: {test data: %#v / expected: (%#v, %#v) / received: (%#v, %#v)}", testName, td.input, td.matched, td.unmatched, matched, unmatched)
+		}
+	}
+}
+
+func TestChunk(t *testing.T) {
+	testName := "TestChunk"
+	testData := []struct {
+		input    []int
+		size     int
+		expected [][]int
+	}{
+		{nil, 2, [][]int{}},
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{[]int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{[]int{1, 2, 3}, 5, [][]int{{1, 2, 3}}},
+		{[]int{1, 2, 3}, 1, [][]int{{1}, {2}, {3}}},
+	}
+	for _, td := range testData {
+		result := Chunk(td.input, td.size)
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v, %#v / expected: %#v / received: %#v}", testName, td.input, td.size, td.expected, result)
+		}
+	}
+
+	// appending to a chunk must not overwrite the next chunk
+	input := []int{1, 2, 3, 4}
+	chunks := Chunk(input, 2)
+	_ = append(chunks[0], 99)
+	if v, expected := chunks[1], []int{3, 4}; !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("%s failed: expected panic for non-positive size", testName)
+		}
+	}()
+	Chunk(input, 0)
+}
+
+func TestWindow(t *testing.T) {
+	testName := "TestWindow"
+	testData := []struct {
+		input    []int
+		size     int
+		expected [][]int
+	}{
+		{nil, 2, [][]int{}},
+		{[]int{1}, 2, [][]int{}},
+		{[]int{1, 2}, 2, [][]int{{1, 2}}},
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {2, 3}, {3, 4}}},
+		{[]int{1, 2, 3, 4}, 3, [][]int{{1, 2, 3}, {2, 3, 4}}},
+		{[]int{1, 2, 3}, 1, [][]int{{1}, {2}, {3}}},
+	}
+	for _, td := range testData {
+		result := Window(td.input, td.size)
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v, %#v / expected: %#v / received: %#v}", testName, td.input, td.size, td.expected, result)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatalf("%s failed: expected panic for non-positive size", testName)
+		}
+	}()
+	Window([]int{1}, -1)
+}
+
+func TestZipUnzip(t *testing.T) {
+	testName := "TestZipUnzip"
+	testData := []struct {
+		a        []string
+		b        []int
+		expected []Pair[string, int]
+	}{
+		{nil, nil, []Pair[string, int]{}},
+		{[]string{"a", "b"}, []int{1, 2}, []Pair[string, int]{{"a", 1}, {"b", 2}}},
+		{[]string{"a", "b", "c"}, []int{1}, []Pair[string, int]{{"a", 1}}},
+		{[]string{"a"}, []int{1, 2}, []Pair[string, int]{{"a", 1}}},
+	}
+	for _, td := range testData {
+		result := Zip(td.a, td.b)
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v, %#v / expected: %#v / received: %#v}", testName, td.a, td.b, td.expected, result)
+		}
+		a, b := Unzip(result)
+		if n := len(result); !reflect.DeepEqual(a, append([]string{}, td.a[:n]...)) || !reflect.DeepEqual(b, append([]int{}, td.b[:n]...)) {
+			t.Fatalf("%s failed: {test data: %#v / received: (%#v, %#v)}", testName, result, a, b)
+		}
+	}
+}
+
+func TestAnyAll(t *testing.T) {
+	testName := "TestAnyAll"
+	testData := []struct {
+		input    []int
+		any, all bool
+	}{
+		{nil, false, true},
+		{[]int{1, 3}, false, false},
+		{[]int{1, 2}, true, false},
+		{[]int{2, 4}, true, true},
+	}
+	for _, td := range testData {
+		if a, b := Any(td.input, isEven), All(td.input, isEven); a != td.any || b != td.all {
+			t.Fatalf("%s failed: {test data: %#v / expected: (%#v, %#v) / received: (%#v, %#v)}", testName, td.input, td.any, td.all, a, b)
+		}
+	}
+}
+
+func TestCountBy(t *testing.T) {
+	testName := "TestCountBy"
+	testData := []struct {
+		input    []int
+		expected map[bool]int
+	}{
+		{nil, map[bool]int{}},
+		{[]int{1, 2, 3, 4, 5}, map[bool]int{true: 2, false: 3}},
+	}
+	for _, td := range testData {
+		result := CountBy(td.input, isEven)
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func TestKeyBy(t *testing.T) {
+	testName := "TestKeyBy"
+	type user struct {
+		ID   int
+		Name string
+	}
+	testData := []struct {
+		input    []user
+		expected map[int]user
+	}{
+		{nil, map[int]user{}},
+		{[]user{{1, "a"}, {2, "b"}}, map[int]user{1: {1, "a"}, 2: {2, "b"}}},
+		{[]user{{1, "a"}, {1, "b"}}, map[int]user{1: {1, "b"}}},
+	}
+	for _, td := range testData {
+		result := KeyBy(td.input, func(u user) int { return u.ID })
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func TestReverse(t *testing.T) {
+	testName := "TestReverse"
+	testData := []struct {
+		input    []int
+		expected []int
+	}{
+		{nil, []int{}},
+		{[]int{1}, []int{1}},
+		{[]int{1, 2, 3}, []int{3, 2, 1}},
+		{[]int{1, 2, 3, 4}, []int{4, 3, 2, 1}},
+	}
+	for _, td := range testData {
+		input := append([]int(nil), td.input...)
+		result := Reverse(td.input)
+		if !reflect.DeepEqual(result, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+		if !reflect.DeepEqual(input, td.input) {
+			t.Fatalf("%s failed: input must not be modified, expected %#v but received %#v", testName, input, td.input)
+		}
+	}
+}
+
+func TestFindIndexFunc(t *testing.T) {
+	testName := "TestFindIndexFunc"
+	testData := []struct {
+		input    []int
+		expected int
+	}{
+		{nil, -1},
+		{[]int{1, 3}, -1},
+		{[]int{1, 2, 4}, 1},
+		{[]int{6}, 0},
+	}
+	for _, td := range testData {
+		if result := FindIndexFunc(td.input, isEven); result != td.expected {
+			t.Fatalf("%s failed: {test data: %#v / expected: %#v / received: %#v}", testName, td.input, td.expected, result)
+		}
+	}
+}
+
+func BenchmarkChunk(b *testing.B) {
+	input := make([]int, 10000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Chunk(input, 100)
+	}
+}
+
+func BenchmarkFilter(b *testing.B) {
+	input := make([]int, 10000)
+	for i := range input {
+		input[i] = i
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Filter(input, isEven)
+	}
+}
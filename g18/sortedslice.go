@@ -0,0 +1,85 @@
+package g18
+
+import "sort"
+
+// SortedSlice is a slice kept in ascending order, with binary-search lookups. Duplicated elements are allowed.
+//
+// Lookups (Has, IndexOf, Rank, Between) are O(log n); Insert and Remove are O(log n) to search plus O(n) to shift
+// elements. The zero value is an empty slice ready to use. A SortedSlice is not safe for concurrent use.
+//
+// @Available since <<VERSION>>
+type SortedSlice[T Sortable] struct {
+	items []T
+}
+
+// NewSortedSlice creates a new SortedSlice containing the provided elements.
+//
+// @Available since <<VERSION>>
+func NewSortedSlice[T Sortable](elems ...T) *SortedSlice[T] {
+	items := append(make([]T, 0, len(elems)), elems...)
+	sort.Slice(items, func(i, j int) bool { return items[i] < items[j] })
+	return &SortedSlice[T]{items: items}
+}
+
+// Len returns the number of elements.
+func (s *SortedSlice[T]) Len() int {
+	return len(s.items)
+}
+
+// At returns the element at position i (in ascending order). This function panics if i is out of range.
+func (s *SortedSlice[T]) At(i int) T {
+	return s.items[i]
+}
+
+// Slice returns a copy of the elements, in ascending order.
+func (s *SortedSlice[T]) Slice() []T {
+	return append(make([]T, 0, len(s.items)), s.items...)
+}
+
+// Rank returns the number of elements that are less than v, which is also the position where v would be inserted.
+func (s *SortedSlice[T]) Rank(v T) int {
+	return sort.Search(len(s.items), func(i int) bool { return s.items[i] >= v })
+}
+
+// IndexOf returns the position of the first occurrence of v. -1 is returned if not found.
+func (s *SortedSlice[T]) IndexOf(v T) int {
+	if i := s.Rank(v); i < len(s.items) && s.items[i] == v {
+		return i
+	}
+	return -1
+}
+
+// Has checks if v is in the slice.
+func (s *SortedSlice[T]) Has(v T) bool {
+	return s.IndexOf(v) >= 0
+}
+
+// Insert adds v, keeping the slice sorted, and returns its position. Duplicated elements are inserted after existing ones.
+func (s *SortedSlice[T]) Insert(v T) int {
+	i := sort.Search(len(s.items), func(i int) bool { return s.items[i] > v })
+	var zero T
+	s.items = append(s.items, zero)
+	copy(s.items[i+1:], s.items[i:])
+	s.items[i] = v
+	return i
+}
+
+// Remove removes one occurrence of v. The return value is false if v is not in the slice.
+func (s *SortedSlice[T]) Remove(v T) bool {
+	i := s.IndexOf(v)
+	if i < 0 {
+		return false
+	}
+	s.items = append(s.items[:i], s.items[i+1:]...)
+	return true
+}
+
+// Between returns a copy of the elements e where lo <= e <= hi, in ascending order.
+func (s *SortedSlice[T]) Between(lo, hi T) []T {
+	from := s.Rank(lo)
+	to := sort.Search(len(s.items), func(i int) bool { return s.items[i] > hi })
+	if from >= to {
+		return make([]T, 0)
+	}
+	return append(make([]T, 0, to-from), s.items[from:to]...)
+}
@@ -0,0 +1,111 @@
+package g18
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSortedSlice_InsertRemove(t *testing.T) {
+	testName := "TestSortedSlice_InsertRemove"
+	var s SortedSlice[int] // zero value is ready to use
+	testData := []struct {
+		v        int
+		pos      int
+		expected []int
+	}{
+		{5, 0, []int{5}},
+		{1, 0, []int{1, 5}},
+		{9, 2, []int{1, 5, 9}},
+		{5, 2, []int{1, 5, 5, 9}},
+		{3, 1, []int{1, 3, 5, 5, 9}},
+	}
+	for _, td := range testData {
+		if pos := s.Insert(td.v); pos != td.pos || !reflect.DeepEqual(s.Slice(), td.expected) {
+			t.Fatalf("%s failed: Insert(%#v) expected (%#v, %#v) but received (%#v, %#v)", testName, td.v, td.pos, td.expected, pos, s.Slice())
+		}
+	}
+	if !s.Remove(5) || !reflect.DeepEqual(s.Slice(), []int{1, 3, 5, 9}) {
+		t.Fatalf("%s failed: Remove must remove one occurrence, received %#v", testName, s.Slice())
+	}
+	if s.Remove(4) || s.Len() != 4 {
+		t.Fatalf("%s failed: Remove must fail for missing elements", testName)
+	}
+	if s.At(0) != 1 || s.At(s.Len()-1) != 9 {
+		t.Fatalf("%s failed: unexpected first/last elements %#v", testName, s.Slice())
+	}
+}
+
+func TestSortedSlice_Lookup(t *testing.T) {
+	testName := "TestSortedSlice_Lookup"
+	s := NewSortedSlice("d", "b", "b", "f")
+	testData := []struct {
+		v     string
+		rank  int
+		index int
+	}{
+		{"a", 0, -1},
+		{"b", 0, 0},
+		{"c", 2, -1},
+		{"d", 2, 2},
+		{"f", 3, 3},
+		{"g", 4, -1},
+	}
+	for _, td := range testData {
+		if rank, index, has := s.Rank(td.v), s.IndexOf(td.v), s.Has(td.v); rank != td.rank || index != td.index || has != (td.index >= 0) {
+			t.Fatalf("%s failed: {test data: %#v / received: (%#v, %#v, %#v)}", testName, td, rank, index, has)
+		}
+	}
+}
+
+func TestSortedSlice_Between(t *testing.T) {
+	testName := "TestSortedSlice_Between"
+	s := NewSortedSlice(1.5, 3.0, 3.0, 4.5, 7.0)
+	testData := []struct {
+		lo, hi   float64
+		expected []float64
+	}{
+		{0, 1, []float64{}},
+		{0, 10, []float64{1.5, 3.0, 3.0, 4.5, 7.0}},
+		{3, 4.5, []float64{3.0, 3.0, 4.5}},
+		{3.5, 4, []float64{}},
+		{7, 7, []float64{7}},
+		{5, 2, []float64{}},
+	}
+	for _, td := range testData {
+		if v := s.Between(td.lo, td.hi); !reflect.DeepEqual(v, td.expected) {
+			t.Fatalf("%s failed: {test data: %#v, %#v / expected: %#v / received: %#v}", testName, td.lo, td.hi, td.expected, v)
+		}
+	}
+
+	// results are copies
+	v := s.Between(0, 10)
+	v[0] = 100
+	if s.At(0) != 1.5 {
+		t.Fatalf("%s failed: modifying the result must not modify the slice", testName)
+	}
+}
+
+func TestSortedSlice_Random(t *testing.T) {
+	testName := "TestSortedSlice_Random"
+	r := rand.New(rand.NewSource(1))
+	s := NewSortedSlice[int]()
+	expected := make([]int, 0)
+	for i := 0; i < 2000; i++ {
+		v := r.Intn(100)
+		if r.Intn(3) == 0 {
+			if j := FindInSlice(v, expected); j >= 0 {
+				expected = append(expected[:j], expected[j+1:]...)
+			}
+			s.Remove(v)
+		} else {
+			expected = append(expected, v)
+			s.Insert(v)
+		}
+	}
+	sort.Ints(expected)
+	if v := s.Slice(); !reflect.DeepEqual(v, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", testName, expected, v)
+	}
+}